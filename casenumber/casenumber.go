@@ -0,0 +1,140 @@
+package casenumber
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnrecognized is wrapped by the errors of case numbers written in a form the parser does not know.
+var ErrUnrecognized = errors.New("unrecognized case number")
+
+// Types maps the abbreviations of the case types to their long names.
+var Types = map[string]string{
+	"CA":  "Civil Appeal",
+	"CCA": "Criminal Appeal",
+	"CM":  "Criminal Motion",
+	"CRF": "Criminal Reference",
+	"CR":  "Criminal Revision",
+	"CC":  "Criminal Case",
+	"MA":  "Magistrate's Appeal",
+	"S":   "Suit",
+	"OS":  "Originating Summons",
+	"OC":  "Originating Claim",
+	"OA":  "Originating Application",
+	"SUM": "Summons",
+	"RA":  "Registrar's Appeal",
+	"DCA": "District Court Appeal",
+	"B":   "Bankruptcy",
+	"CWU": "Companies Winding Up",
+	"ADM": "Admiralty in Rem",
+}
+
+// courts are the prefixes naming the court of an abbreviated case number, as in "HC/S" or "CA/CM".
+var courts = map[string]bool{"CA": true, "HC": true, "AD": true, "SIC": true, "HCF": true, "FC": true}
+
+var (
+	longRegex  = regexp.MustCompile(`(?i)^([a-z' ]+?)\s+No\.?\s*(\d+)\s+of\s+(\d{4})`)
+	shortRegex = regexp.MustCompile(`^([A-Za-z]+(?:\s*/\s*[A-Za-z]+)*)\s*(\d+)\s*/\s*(\d{4})(?:\s*/\s*(\d+))?`)
+	typeByName = func() map[string]string {
+		byName := map[string]string{}
+		for abbreviation, name := range Types {
+			byName[strings.ToLower(name)] = abbreviation
+		}
+		byName["magistrates' appeal"] = "MA"
+		byName["magistrate appeal"] = "MA"
+		return byName
+	}()
+)
+
+// CaseNumber is a case number such as "Criminal Appeal No 12 of 2022" or "MA 9123/2021/01".
+type CaseNumber struct {
+	// Text is the case number as written
+	Text string `json:"text"`
+	// Court is the court prefix of abbreviated numbers, "HC" in "HC/S 123/2019"
+	Court string `json:"court,omitempty"`
+	// Type is the abbreviation of the case type, "CCA" for "Criminal Appeal", empty when unknown
+	Type   string `json:"type"`
+	Serial int    `json:"serial"`
+	Year   int    `json:"year"`
+	// Sub is the trailing number of "MA 9123/2021/01", which tells apart the proceedings of one case
+	Sub int `json:"sub,omitempty"`
+
+	// legacy is set on numbers decoded from a plain string, so that they differ from a fresh parse and get stored again
+	legacy bool
+}
+
+// Parse reads a case number in its long form, "Suit No 123 of 2019", or abbreviated, "HC/S 123/2019".
+// A case number it does not recognize is returned with only its Text, along with ErrUnrecognized.
+func Parse(text string) (CaseNumber, error) {
+	n := CaseNumber{Text: strings.TrimSpace(text)}
+	normalized := strings.NewReplacer("’", "'", "‘", "'").Replace(n.Text)
+
+	if match := longRegex.FindStringSubmatch(normalized); match != nil {
+		n.Type = typeByName[strings.ToLower(strings.Join(strings.Fields(match[1]), " "))]
+		n.Serial, _ = strconv.Atoi(match[2])
+		n.Year, _ = strconv.Atoi(match[3])
+		return n, nil
+	}
+
+	if match := shortRegex.FindStringSubmatch(normalized); match != nil {
+		parts := strings.Split(strings.ToUpper(strings.ReplaceAll(match[1], " ", "")), "/")
+		if len(parts) > 1 && courts[parts[0]] {
+			n.Court, parts = parts[0], parts[1:]
+		}
+		n.Type = strings.Join(parts, "/")
+		n.Serial, _ = strconv.Atoi(match[2])
+		n.Year, _ = strconv.Atoi(match[3])
+		if match[4] != "" {
+			n.Sub, _ = strconv.Atoi(match[4])
+		}
+		return n, nil
+	}
+
+	return n, fmt.Errorf("%w: %q", ErrUnrecognized, text)
+}
+
+// Name returns the long name of the case type, empty when it is unknown.
+func (n CaseNumber) Name() string {
+	return Types[n.Type]
+}
+
+// Key groups the proceedings of a case: the long and abbreviated forms of a number share it, whatever their court prefix
+// and sub number, "cca-12-2022". It is empty for unrecognized numbers and types.
+func (n CaseNumber) Key() string {
+	if n.Type == "" || n.Serial == 0 || n.Year == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d-%d", strings.ToLower(strings.ReplaceAll(n.Type, "/", "-")), n.Serial, n.Year)
+}
+
+// MarshalJSON adds the long name of the type and the key to the fields, so they can be queried in the stored metadata.
+func (n CaseNumber) MarshalJSON() ([]byte, error) {
+	type fields CaseNumber
+	return json.Marshal(struct {
+		fields
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	}{fields(n), n.Name(), n.Key()})
+}
+
+// UnmarshalJSON also reads the plain strings case numbers were stored as, parsing them.
+func (n *CaseNumber) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*n, _ = Parse(text)
+		n.legacy = true
+		return nil
+	}
+
+	type fields CaseNumber
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*n = CaseNumber(f)
+	return nil
+}
@@ -0,0 +1,97 @@
+package casenumber
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want CaseNumber
+		key  string
+	}{
+		{
+			text: "Criminal Appeal No 12 of 2022",
+			want: CaseNumber{Text: "Criminal Appeal No 12 of 2022", Type: "CCA", Serial: 12, Year: 2022},
+			key:  "cca-12-2022",
+		},
+		{
+			text: "Magistrate’s Appeal No. 9112 of 2023",
+			want: CaseNumber{Text: "Magistrate’s Appeal No. 9112 of 2023", Type: "MA", Serial: 9112, Year: 2023},
+			key:  "ma-9112-2023",
+		},
+		{
+			text: "MA 9123/2021/01",
+			want: CaseNumber{Text: "MA 9123/2021/01", Type: "MA", Serial: 9123, Year: 2021, Sub: 1},
+			key:  "ma-9123-2021",
+		},
+		{
+			text: "HC/S 123/2019",
+			want: CaseNumber{Text: "HC/S 123/2019", Court: "HC", Type: "S", Serial: 123, Year: 2019},
+			key:  "s-123-2019",
+		},
+		{
+			text: "CA/CM 5/2023",
+			want: CaseNumber{Text: "CA/CM 5/2023", Court: "CA", Type: "CM", Serial: 5, Year: 2023},
+			key:  "cm-5-2023",
+		},
+		{
+			text: "Originating Summons No 5 of 2020 (Summons No 12 of 2020)",
+			want: CaseNumber{Text: "Originating Summons No 5 of 2020 (Summons No 12 of 2020)", Type: "OS", Serial: 5, Year: 2020},
+			key:  "os-5-2020",
+		},
+		{
+			text: "Special Case No 1 of 2021",
+			want: CaseNumber{Text: "Special Case No 1 of 2021", Serial: 1, Year: 2021},
+			key:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+			if got.Key() != tt.key {
+				t.Errorf("Key() = %q, want %q", got.Key(), tt.key)
+			}
+		})
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	got, err := Parse("Tribunal reference 7")
+	if !errors.Is(err, ErrUnrecognized) {
+		t.Errorf("Parse() error = %v, want ErrUnrecognized", err)
+	}
+	if got.Text != "Tribunal reference 7" || got.Key() != "" {
+		t.Errorf("Parse() = %+v, want only the text", got)
+	}
+}
+
+func TestJson(t *testing.T) {
+	var numbers []CaseNumber
+	if err := json.Unmarshal([]byte(`["MA 312/2008", {"text": "HC/S 123/2019", "court": "HC", "type": "S", "serial": 123, "year": 2019}]`), &numbers); err != nil {
+		t.Fatal(err)
+	}
+	if numbers[0].Key() != "ma-312-2008" || numbers[1].Key() != "s-123-2019" {
+		t.Errorf("Unmarshal() = %+v, want the legacy string parsed", numbers)
+	}
+	if parsed, _ := Parse("MA 312/2008"); reflect.DeepEqual(numbers[0], parsed) {
+		t.Error("a number decoded from a string equals a fresh parse, it would not be stored again")
+	}
+
+	data, err := json.Marshal(numbers[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"text":"HC/S 123/2019","court":"HC","type":"S","serial":123,"year":2019,"name":"Suit","key":"s-123-2019"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,108 @@
+package citation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is wrapped by the errors of text that is not a valid neutral citation.
+var ErrInvalid = errors.New("invalid citation")
+
+// FirstYear is the year neutral citations were introduced in Singapore.
+const FirstYear = 2000
+
+// Courts are the court codes of the neutral citations of the Supreme Court and the State Courts.
+var Courts = []string{
+	"SGCA", "SGCA(I)", "SGHC", "SGHC(I)", "SGHCA", "SGHCF", "SGHCR",
+	"SGDC", "SGMC", "SGFC", "SGYC", "SGPC",
+}
+
+var (
+	citationRegex = regexp.MustCompile(`^\[\s*(\d{4})\s*\]\s*([A-Za-z]+(?:\s*\(\s*[Ii]\s*\))?)\s*(\d+)$`)
+	slugRegex     = regexp.MustCompile(`^(\d{4})[_-]([A-Za-z]+)[_-](\d+)$`)
+)
+
+// Citation is a neutral citation such as "[2023] SGHC 123".
+type Citation struct {
+	Year int `json:"year"`
+	// Court is the court code in upper case, "SGHC(I)" for the SICC
+	Court  string `json:"court"`
+	Serial int    `json:"serial"`
+}
+
+// Parse reads a neutral citation, ignoring the "|" separators and spacing of the listing.
+// "[2023] SGHC 123", "[2023]SGHC 123" and "[2020] SGHC (I) 2" are all accepted.
+func Parse(text string) (Citation, error) {
+	cleaned := strings.TrimSpace(strings.ReplaceAll(text, "|", ""))
+	match := citationRegex.FindStringSubmatch(cleaned)
+	if match == nil {
+		return Citation{}, fmt.Errorf("%w: %q", ErrInvalid, text)
+	}
+	court := strings.ToUpper(strings.Join(strings.Fields(match[2]), ""))
+	return newCitation(match[1], court, match[3], text)
+}
+
+// FromSlug reads the citation of a judgement url slug such as "2023_SGHC_123", "SGHCI" standing for "SGHC(I)".
+func FromSlug(slug string) (Citation, error) {
+	match := slugRegex.FindStringSubmatch(slug)
+	if match == nil {
+		return Citation{}, fmt.Errorf("%w: slug %q", ErrInvalid, slug)
+	}
+	court := strings.ToUpper(match[2])
+	if strings.HasSuffix(court, "I") && slices.Contains(Courts, strings.TrimSuffix(court, "I")+"(I)") {
+		court = strings.TrimSuffix(court, "I") + "(I)"
+	}
+	return newCitation(match[1], court, match[3], slug)
+}
+
+func newCitation(year string, court string, serial string, text string) (Citation, error) {
+	c := Citation{Court: court}
+	c.Year, _ = strconv.Atoi(year)
+	c.Serial, _ = strconv.Atoi(serial)
+	if err := c.Validate(); err != nil {
+		return Citation{}, fmt.Errorf("%w in %q", err, text)
+	}
+	return c, nil
+}
+
+// Validate checks that the year is FirstYear or later, the court is known and the serial positive.
+// The year has no upper bound, so a citation validates the same whenever it is parsed.
+func (c Citation) Validate() error {
+	if c.Year < FirstYear {
+		return fmt.Errorf("%w: year %d", ErrInvalid, c.Year)
+	}
+	if !slices.Contains(Courts, c.Court) {
+		return fmt.Errorf("%w: court %s", ErrInvalid, c.Court)
+	}
+	if c.Serial <= 0 {
+		return fmt.Errorf("%w: serial %d", ErrInvalid, c.Serial)
+	}
+	return nil
+}
+
+// String returns the canonical form of the citation, "[2023] SGHC 123".
+func (c Citation) String() string {
+	return fmt.Sprintf("[%d] %s %d", c.Year, c.Court, c.Serial)
+}
+
+// Slug returns a stable lower case key of the citation for joins with other sources, "2023-sghc-123".
+// The SICC code "SGHC(I)" gives "sghci".
+func (c Citation) Slug() string {
+	court := strings.NewReplacer("(", "", ")", "").Replace(strings.ToLower(c.Court))
+	return fmt.Sprintf("%d-%s-%d", c.Year, court, c.Serial)
+}
+
+// MarshalJSON adds the canonical form and the slug to the fields, so they can be queried in the stored metadata.
+func (c Citation) MarshalJSON() ([]byte, error) {
+	type fields Citation
+	return json.Marshal(struct {
+		fields
+		Canonical string `json:"canonical"`
+		Slug      string `json:"slug"`
+	}{fields(c), c.String(), c.Slug()})
+}
@@ -0,0 +1,76 @@
+package citation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text      string
+		canonical string
+		slug      string
+	}{
+		{text: "[2023] SGHC 123", canonical: "[2023] SGHC 123", slug: "2023-sghc-123"},
+		{text: " | [2023]  SGHC 123 | ", canonical: "[2023] SGHC 123", slug: "2023-sghc-123"},
+		{text: "[2023]SGCA 5", canonical: "[2023] SGCA 5", slug: "2023-sgca-5"},
+		{text: "[2020] SGHC (I) 2", canonical: "[2020] SGHC(I) 2", slug: "2020-sghci-2"},
+		{text: "[2022] sghca 07", canonical: "[2022] SGHCA 7", slug: "2022-sghca-7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			c, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if c.String() != tt.canonical || c.Slug() != tt.slug {
+				t.Errorf("Parse() = %s, %s, want %s, %s", c, c.Slug(), tt.canonical, tt.slug)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, text := range []string{"", "SGHC 123", "[1999] SGHC 1", "[2023] SGXX 1", "[2023] SGHC 0", "[2023] SGHC 12a"} {
+		if _, err := Parse(text); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", text, err)
+		}
+	}
+}
+
+func TestFromSlug(t *testing.T) {
+	tests := []struct {
+		slug      string
+		canonical string
+	}{
+		{slug: "2023_SGHC_123", canonical: "[2023] SGHC 123"},
+		{slug: "2020_SGHCI_2", canonical: "[2020] SGHC(I) 2"},
+		{slug: "2023-sghc-123", canonical: "[2023] SGHC 123"},
+	}
+	for _, tt := range tests {
+		c, err := FromSlug(tt.slug)
+		if err != nil {
+			t.Fatalf("FromSlug(%s) error = %v", tt.slug, err)
+		}
+		if c.String() != tt.canonical {
+			t.Errorf("FromSlug(%s) = %s, want %s", tt.slug, c, tt.canonical)
+		}
+	}
+}
+
+func TestJson(t *testing.T) {
+	c := Citation{Year: 2023, Court: "SGHC", Serial: 123}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"year":2023,"court":"SGHC","serial":123,"canonical":"[2023] SGHC 123","slug":"2023-sghc-123"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded Citation
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != c {
+		t.Errorf("Unmarshal() = %+v, %v, want %+v", decoded, err, c)
+	}
+}
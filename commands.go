@@ -0,0 +1,808 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/crawler"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"lexicon/singapore-supreme-court-crawler/scrapper"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultCommand = "run"
+
+type command struct {
+	Name    string
+	Aliases []string
+	Usage   string
+	Flags   *flag.FlagSet
+	Run     func(ctx context.Context) error
+}
+
+func newCommands(cfg config) []*command {
+	return []*command{
+		newCrawlCommand(cfg),
+		newScrapeCommand(cfg),
+		newRunCommand(cfg),
+		newWorkerCommand(cfg),
+		newReparseCommand(cfg),
+		newSweepCommand(cfg),
+		newExportCommand(cfg),
+		newStatusCommand(cfg),
+	}
+}
+
+func findCommand(commands []*command, name string) *command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+		for _, alias := range cmd.Aliases {
+			if alias == name {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+func printUsage(commands []*command) {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.Name, cmd.Usage)
+	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command. Defaults to '%s'.\n", os.Args[0], defaultCommand)
+}
+
+func newFlagSet(name string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s [flags]\n\n%s\n\nFlags:\n", os.Args[0], name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+/* Crawl */
+
+// crawlJobFlags selects the crawl jobs from a jobs file or a single search given on the command line.
+type crawlJobFlags struct {
+	jobsFile      *string
+	searchPhrase  *string
+	filter        *string
+	year          *string
+	sortBy        *string
+	sortAscending *bool
+}
+
+func addCrawlJobFlags(flags *flag.FlagSet, cfg config) crawlJobFlags {
+	defaults := crawler.DefaultCrawlJob()
+	return crawlJobFlags{
+		jobsFile:      flags.String("jobs", cfg.Crawl.JobsFile, "JSON file with the list of crawl jobs (env CRAWL_JOBS_FILE)"),
+		searchPhrase:  flags.String("search-phrase", "", "crawl a single search instead of the jobs file, e.g. 'CatchWords:Money Laundering'"),
+		filter:        flags.String("filter", defaults.Filter, "court filter of the single search"),
+		year:          flags.String("year", defaults.YearOfDecision, "year of decision of the single search"),
+		sortBy:        flags.String("sort-by", defaults.SortBy, "sort field of the single search"),
+		sortAscending: flags.Bool("sort-ascending", defaults.SortAscending, "sort the single search in ascending order"),
+	}
+}
+
+// addFetcherFlag lets a run override the FETCHER of cfg.
+func addFetcherFlag(flags *flag.FlagSet, cfg *config) {
+	flags.StringVar(&cfg.Fetch.Fetcher, "fetcher", cfg.Fetch.Fetcher, "download pages with the headless browser or over plain http (env FETCHER)")
+}
+
+func (f crawlJobFlags) jobs() ([]crawler.CrawlJob, error) {
+	if *f.searchPhrase != "" {
+		job := crawler.CrawlJob{
+			Filter:         *f.filter,
+			YearOfDecision: *f.year,
+			SearchPhrase:   *f.searchPhrase,
+			SortBy:         *f.sortBy,
+			SortAscending:  *f.sortAscending,
+		}.WithDefaults()
+		if err := job.Validate(); err != nil {
+			return nil, err
+		}
+		return []crawler.CrawlJob{job}, nil
+	}
+
+	if *f.jobsFile != "" {
+		return crawler.LoadCrawlJobs(*f.jobsFile)
+	}
+
+	return []crawler.CrawlJob{crawler.DefaultCrawlJob()}, nil
+}
+
+func newCrawlCommand(cfg config) *command {
+	usage := "Crawl the elitigation.sg listing pages into the url frontier."
+	flags := newFlagSet("crawl", usage)
+	timeout := flags.Duration("timeout", 0, "abort the crawl after this duration (0 means no limit)")
+	url := flags.String("url", "", "crawl only this listing page url")
+	enqueue := flags.Bool("enqueue", false, "publish the listing pages to the work queue for crawl workers instead of crawling them")
+	incremental := flags.Bool("incremental", false, "stop paging each job once only known judgements are listed")
+	jobFlags := addCrawlJobFlags(flags, cfg)
+	addFetcherFlag(flags, &cfg)
+
+	return &command{
+		Name:    "crawl",
+		Aliases: []string{"crawler"},
+		Usage:   usage,
+		Flags:   flags,
+		Run: func(ctx context.Context) error {
+			jobs, err := jobFlags.jobs()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := withTimeout(ctx, *timeout)
+			defer cancel()
+
+			closeDatabase, err := setupDatabase(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDatabase()
+
+			if err := setupFetch(ctx, cfg); err != nil {
+				return err
+			}
+
+			// With a work queue, crawled frontiers are published for the scrape workers
+			if cfg.Nats.Url != "" || *enqueue {
+				closeJetStream, err := setupJetStream(ctx, cfg)
+				if err != nil {
+					return err
+				}
+				defer closeJetStream()
+			}
+
+			if *enqueue {
+				return runEnqueue(ctx, cfg, jobs)
+			}
+			if *url != "" {
+				return runCrawlUrl(ctx, cfg, *url)
+			}
+			return runCrawl(ctx, cfg, jobs, *incremental)
+		},
+	}
+}
+
+func newCrawler(cfg config) (crawler.CrawlerImpl, error) {
+	httpFetcher, err := cfg.Fetch.HttpFetcher(common.HttpClient)
+	if err != nil {
+		return crawler.CrawlerImpl{}, err
+	}
+	return crawler.CrawlerImpl{
+		MaxDeliver:  int(cfg.Nats.MaxDeliver),
+		HttpFetcher: httpFetcher,
+		UserAgent:   cfg.Fetch.UserAgent,
+		Concurrency: int(cfg.Crawl.Concurrency),
+		FetchRetry:  cfg.Fetch.RetryPolicy(),
+		Stats:       retry.NewStats(),
+	}, nil
+}
+
+func runCrawl(ctx context.Context, cfg config, jobs []crawler.CrawlJob, incremental bool) error {
+	crawler, err := newCrawler(cfg)
+	if err != nil {
+		return err
+	}
+	crawler.Jobs = jobs
+	crawler.Incremental = incremental
+	crawler.Setup()
+	defer crawler.Teardown()
+
+	return crawler.CrawlAll(ctx)
+}
+
+func runEnqueue(ctx context.Context, cfg config, jobs []crawler.CrawlJob) error {
+	crawler, err := newCrawler(cfg)
+	if err != nil {
+		return err
+	}
+	crawler.Jobs = jobs
+	crawler.Setup()
+	defer crawler.Teardown()
+
+	return crawler.EnqueueAll(ctx)
+}
+
+func runCrawlUrl(ctx context.Context, cfg config, url string) error {
+	crawler, err := newCrawler(cfg)
+	if err != nil {
+		return err
+	}
+	crawler.Setup()
+	defer crawler.Teardown()
+
+	return crawler.Crawl(ctx, url)
+}
+
+/* Scrape */
+
+func newScrapeCommand(cfg config) *command {
+	usage := "Scrape every pending url frontier and upload its artifacts."
+	flags := newFlagSet("scrape", usage)
+	timeout := flags.Duration("timeout", 0, "abort the scrape after this duration (0 means no limit)")
+	url := flags.String("url", "", "scrape only this judgement url, e.g. to refresh an amended judgement")
+	rescrape := flags.Bool("rescrape", false, "scrape every already scraped url frontier again to detect amended judgements")
+	addFetcherFlag(flags, &cfg)
+
+	return &command{
+		Name:    "scrape",
+		Aliases: []string{"scrapper"},
+		Usage:   usage,
+		Flags:   flags,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := withTimeout(ctx, *timeout)
+			defer cancel()
+
+			closeAll, err := setupAll(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeAll()
+
+			if err := setupFetch(ctx, cfg); err != nil {
+				return err
+			}
+
+			if *url != "" {
+				return runScrapeUrl(ctx, cfg, *url)
+			}
+			if *rescrape {
+				return runRescrape(ctx, cfg)
+			}
+			return runScrape(ctx, cfg)
+		},
+	}
+}
+
+func newScrapper(cfg config) (scrapper.ScrapperImpl, error) {
+	httpFetcher, err := cfg.Fetch.HttpFetcher(common.HttpClient)
+	if err != nil {
+		return scrapper.ScrapperImpl{}, err
+	}
+	return scrapper.ScrapperImpl{
+		MaxDeliver:    int(cfg.Nats.MaxDeliver),
+		RetryPolicy:   cfg.Scrape.RetryPolicy(),
+		LeaseDuration: cfg.Scrape.LeaseDuration,
+		HttpFetcher:   httpFetcher,
+		UserAgent:     cfg.Fetch.UserAgent,
+		Concurrency:   int(cfg.Scrape.Concurrency),
+		FetchRetry:    cfg.Fetch.RetryPolicy(),
+		Stats:         retry.NewStats(),
+	}, nil
+}
+
+func runScrape(ctx context.Context, cfg config) error {
+	scrapper, err := newScrapper(cfg)
+	if err != nil {
+		return err
+	}
+	scrapper.Setup()
+	defer scrapper.Teardown()
+
+	return scrapper.ScrapeAll(ctx)
+}
+
+func runRescrape(ctx context.Context, cfg config) error {
+	scrapper, err := newScrapper(cfg)
+	if err != nil {
+		return err
+	}
+	scrapper.Setup()
+	defer scrapper.Teardown()
+
+	return scrapper.RescrapeAll(ctx)
+}
+
+func runScrapeUrl(ctx context.Context, cfg config, url string) error {
+	scrapper, err := newScrapper(cfg)
+	if err != nil {
+		return err
+	}
+	scrapper.Setup()
+	defer scrapper.Teardown()
+
+	return scrapper.Scrape(ctx, url)
+}
+
+/* Run */
+
+func newRunCommand(cfg config) *command {
+	usage := "Crawl the listing pages, then scrape every pending url frontier."
+	flags := newFlagSet("run", usage)
+	timeout := flags.Duration("timeout", 0, "abort the run after this duration (0 means no limit)")
+	incremental := flags.Bool("incremental", false, "stop paging each job once only known judgements are listed")
+	jobFlags := addCrawlJobFlags(flags, cfg)
+	addFetcherFlag(flags, &cfg)
+
+	return &command{
+		Name:    "run",
+		Aliases: []string{"run-both"},
+		Usage:   usage,
+		Flags:   flags,
+		Run: func(ctx context.Context) error {
+			jobs, err := jobFlags.jobs()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := withTimeout(ctx, *timeout)
+			defer cancel()
+
+			closeAll, err := setupAll(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeAll()
+
+			if err := setupFetch(ctx, cfg); err != nil {
+				return err
+			}
+
+			// A partially failed crawl still leaves frontiers worth scraping
+			crawlErr := runCrawl(ctx, cfg, jobs, *incremental)
+			if crawlErr != nil {
+				log.Error().Err(crawlErr).Msg("Crawl finished with errors")
+			}
+
+			return errors.Join(crawlErr, runScrape(ctx, cfg))
+		},
+	}
+}
+
+/* Worker */
+
+func newWorkerCommand(cfg config) *command {
+	usage := "Consume the work queue: listing pages with -role crawl, url frontiers with -role scrape."
+	flags := newFlagSet("worker", usage)
+	role := flags.String("role", "scrape", "queue to consume: crawl or scrape")
+	workers := flags.Int("workers", 1, "number of messages handled concurrently")
+	addFetcherFlag(flags, &cfg)
+
+	return &command{
+		Name:  "worker",
+		Usage: usage,
+		Flags: flags,
+		Run: func(ctx context.Context) error {
+			if *workers <= 0 {
+				return fmt.Errorf("workers must be positive, got %d", *workers)
+			}
+
+			var closeDependencies func()
+			var err error
+			switch *role {
+			case "crawl":
+				closeDependencies, err = setupDatabase(ctx, cfg)
+			case "scrape":
+				closeDependencies, err = setupAll(ctx, cfg)
+			default:
+				return fmt.Errorf("unknown role %q, expected crawl or scrape", *role)
+			}
+			if err != nil {
+				return err
+			}
+			defer closeDependencies()
+
+			if err := setupFetch(ctx, cfg); err != nil {
+				return err
+			}
+
+			closeJetStream, err := setupJetStream(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeJetStream()
+
+			maxDeliver := int(cfg.Nats.MaxDeliver)
+			if *role == "crawl" {
+				consumer, err := crawler_service.EnsureConsumer(ctx, common.NATS_CRAWLER_CONSUMER, common.NATS_LISTING_SUBJECT, maxDeliver)
+				if err != nil {
+					return err
+				}
+
+				crawler, err := newCrawler(cfg)
+				if err != nil {
+					return err
+				}
+				crawler.Setup()
+				defer crawler.Teardown()
+
+				log.Info().Msgf("Consuming listing pages with %d workers", *workers)
+				return ignoreCanceled(crawler_service.ConsumeMessages(ctx, consumer, *workers, crawler.Consume))
+			}
+
+			consumer, err := crawler_service.EnsureConsumer(ctx, common.NATS_SCRAPPER_CONSUMER, common.NATS_URL_FRONTIER_SUBJECT, maxDeliver)
+			if err != nil {
+				return err
+			}
+
+			scrapper, err := newScrapper(cfg)
+			if err != nil {
+				return err
+			}
+			scrapper.Setup()
+			defer scrapper.Teardown()
+
+			go requeueDueUrlFrontiers(ctx, cfg.Nats.RequeueInterval)
+
+			log.Info().Msgf("Consuming url frontiers with %d workers", *workers)
+			return ignoreCanceled(crawler_service.ConsumeMessages(ctx, consumer, *workers, scrapper.Consume))
+		},
+	}
+}
+
+// requeueDueUrlFrontiers publishes the url frontiers due to be scraped every interval until ctx is done.
+// Every scrape worker runs it, the message ids drop the url frontiers another worker already queued.
+func requeueDueUrlFrontiers(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		published, err := crawler_service.RequeueDueUrlFrontiers(ctx, requeueBatchSize)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Error().Err(err).Msg("Error requeueing due url frontiers")
+		} else if published > 0 {
+			log.Info().Msgf("Requeued %d due url frontiers", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+const requeueBatchSize = 500
+
+// ignoreCanceled treats a shutdown requested by a signal as a clean exit.
+func ignoreCanceled(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+/* Reparse */
+
+func newReparseCommand(cfg config) *command {
+	usage := "Rebuild extraction metadata from the stored HTML without network access."
+	flags := newFlagSet("reparse", usage)
+	since := flags.String("since", "", "only reparse extractions updated at or after this RFC3339 time or YYYY-MM-DD date")
+	batchSize := flags.Int("batch", 500, "number of extractions reparsed per query")
+	dryRun := flags.Bool("dry-run", false, "report the changed fields without storing them")
+	pdfText := flags.Bool("pdf", false, "also extract the text of the stored PDFs")
+	asJson := flags.Bool("json", false, "print the report as JSON")
+
+	return &command{
+		Name:  "reparse",
+		Usage: usage,
+		Flags: flags,
+		Run: func(ctx context.Context) error {
+			sinceTime, err := parseSince(*since)
+			if err != nil {
+				return err
+			}
+			if *batchSize <= 0 {
+				return fmt.Errorf("batch must be positive, got %d", *batchSize)
+			}
+
+			closeDatabase, err := setupDatabase(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDatabase()
+
+			if *pdfText {
+				closeStorage, err := setupStorage(ctx, cfg)
+				if err != nil {
+					return err
+				}
+				defer closeStorage()
+			}
+
+			report, err := scrapper.ReparseAll(ctx, scrapper.ReparseOptions{
+				Since:     sinceTime,
+				BatchSize: int32(*batchSize),
+				DryRun:    *dryRun,
+				PdfText:   *pdfText,
+			})
+			if err != nil {
+				return err
+			}
+
+			if *asJson {
+				return json.NewEncoder(os.Stdout).Encode(report)
+			}
+
+			fmt.Fprintf(os.Stdout, "Reparsed %d extractions: %d changed, %d skipped, %d failed\n", report.Reparsed, report.Changed, report.Skipped, report.Failed)
+			if *pdfText {
+				fmt.Fprintf(os.Stdout, "Extracted the text of %d PDFs\n", report.PdfTexts)
+			}
+			if len(report.Fields) == 0 {
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "FIELD\tCHANGED")
+			for _, field := range slices.Sorted(maps.Keys(report.Fields)) {
+				fmt.Fprintf(w, "%s\t%d\n", field, report.Fields[field])
+			}
+			return w.Flush()
+		},
+	}
+}
+
+/* Sweep */
+
+func newSweepCommand(cfg config) *command {
+	usage := "Audit the uploaded PDFs and raw pages for error pages and truncated files."
+	flags := newFlagSet("sweep", usage)
+	since := flags.String("since", "", "only sweep extractions updated at or after this RFC3339 time or YYYY-MM-DD date")
+	batchSize := flags.Int("batch", 200, "number of extractions swept per query")
+	fix := flags.Bool("fix", false, "give the url frontiers of invalid artifacts the invalid_artifact status, so the next scrape downloads them again")
+	asJson := flags.Bool("json", false, "print the report as JSON")
+
+	return &command{
+		Name:  "sweep",
+		Usage: usage,
+		Flags: flags,
+		Run: func(ctx context.Context) error {
+			sinceTime, err := parseSince(*since)
+			if err != nil {
+				return err
+			}
+			if *batchSize <= 0 {
+				return fmt.Errorf("batch must be positive, got %d", *batchSize)
+			}
+
+			closeAll, err := setupAll(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeAll()
+
+			report, err := scrapper.SweepArtifacts(ctx, scrapper.SweepOptions{
+				Since:     sinceTime,
+				BatchSize: int32(*batchSize),
+				Fix:       *fix,
+			})
+			if err != nil {
+				return err
+			}
+
+			if *asJson {
+				return json.NewEncoder(os.Stdout).Encode(report)
+			}
+
+			fmt.Fprintf(os.Stdout, "Checked %d artifacts: %d invalid, %d unreadable, %d url frontiers marked\n", report.Checked, report.Invalid, report.Failed, report.Fixed)
+			if len(report.Issues) == 0 {
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "EXTRACTION\tKIND\tREASON\tLINK")
+			for _, issue := range report.Issues {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", issue.ExtractionID, issue.Kind, issue.Reason, issue.Link)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+/* Export */
+
+type exportRecord struct {
+	ID                string                 `json:"id"`
+	UrlFrontierID     string                 `json:"url_frontier_id"`
+	ArtifactLink      *string                `json:"artifact_link"`
+	ArtifactSha256    *string                `json:"artifact_sha256"`
+	ArtifactSize      *int64                 `json:"artifact_size"`
+	RawPageLink       *string                `json:"raw_page_link"`
+	RawPageSha256     *string                `json:"raw_page_sha256"`
+	RawPageSize       *int64                 `json:"raw_page_size"`
+	Language          string                 `json:"language"`
+	PageHash          *string                `json:"page_hash"`
+	Version           int32                  `json:"version"`
+	AmendedAt         *time.Time             `json:"amended_at"`
+	SiteContent       *string                `json:"site_content,omitempty"`
+	PdfText           *string                `json:"pdf_text,omitempty"`
+	PdfPageCount      *int32                 `json:"pdf_page_count"`
+	PdfParagraphPages []models.ParagraphPage `json:"pdf_paragraph_pages"`
+	Metadata          models.Metadata        `json:"metadata"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+func newExportCommand(cfg config) *command {
+	usage := "Export extractions as JSON lines."
+	flags := newFlagSet("export", usage)
+	out := flags.String("out", "-", "file to write to, '-' for stdout")
+	since := flags.String("since", "", "only export extractions updated at or after this RFC3339 time or YYYY-MM-DD date")
+	batchSize := flags.Int("batch", 500, "number of extractions fetched per query")
+	includeContent := flags.Bool("include-content", false, "include the raw judgement HTML and PDF text in every record")
+
+	return &command{
+		Name:  "export",
+		Usage: usage,
+		Flags: flags,
+		Run: func(ctx context.Context) error {
+			sinceTime, err := parseSince(*since)
+			if err != nil {
+				return err
+			}
+			if *batchSize <= 0 {
+				return fmt.Errorf("batch must be positive, got %d", *batchSize)
+			}
+
+			closeDatabase, err := setupDatabase(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDatabase()
+
+			var w io.Writer = os.Stdout
+			if *out != "-" {
+				f, err := os.Create(*out)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", *out, err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			total, err := exportExtractions(ctx, w, sinceTime, int32(*batchSize), *includeContent)
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("Exported %d extractions", total)
+			return nil
+		},
+	}
+}
+
+func parseSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: expected RFC3339 time or YYYY-MM-DD date", since)
+	}
+	return t, nil
+}
+
+func exportExtractions(ctx context.Context, w io.Writer, since time.Time, batchSize int32, includeContent bool) (int, error) {
+	encoder := json.NewEncoder(w)
+	total := 0
+	lastId := ""
+
+	for {
+		extractions, err := services.ListExtractions(ctx, since, lastId, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, extraction := range extractions {
+			record := exportRecord{
+				ID:                extraction.ID,
+				UrlFrontierID:     extraction.UrlFrontierID,
+				ArtifactLink:      extraction.ArtifactLink,
+				ArtifactSha256:    extraction.ArtifactSha256,
+				ArtifactSize:      extraction.ArtifactSize,
+				RawPageLink:       extraction.RawPageLink,
+				RawPageSha256:     extraction.RawPageSha256,
+				RawPageSize:       extraction.RawPageSize,
+				Language:          extraction.Language,
+				PageHash:          extraction.PageHash,
+				Version:           extraction.Version,
+				AmendedAt:         extraction.AmendedAt,
+				PdfPageCount:      extraction.PdfPageCount,
+				PdfParagraphPages: extraction.PdfParagraphPages,
+				Metadata:          extraction.Metadata,
+				CreatedAt:         extraction.CreatedAt,
+				UpdatedAt:         extraction.UpdatedAt,
+			}
+			if includeContent {
+				record.SiteContent = extraction.SiteContent
+				record.PdfText = extraction.PdfText
+			}
+			if err := encoder.Encode(record); err != nil {
+				return total, fmt.Errorf("failed to write extraction %s: %w", extraction.ID, err)
+			}
+			total++
+		}
+
+		if len(extractions) < int(batchSize) {
+			return total, nil
+		}
+		lastId = extractions[len(extractions)-1].ID
+	}
+}
+
+/* Status */
+
+type statusCount struct {
+	Status int16  `json:"status"`
+	Name   string `json:"name"`
+	Total  int64  `json:"total"`
+}
+
+func newStatusCommand(cfg config) *command {
+	usage := "Show the number of url frontiers per status."
+	flags := newFlagSet("status", usage)
+	asJson := flags.Bool("json", false, "print the counts as JSON")
+
+	return &command{
+		Name:  "status",
+		Usage: usage,
+		Flags: flags,
+		Run: func(ctx context.Context) error {
+			closeDatabase, err := setupDatabase(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDatabase()
+
+			counts, err := crawler_service.CountUrlFrontiersByStatus(ctx)
+			if err != nil {
+				return err
+			}
+
+			statuses := make([]statusCount, len(counts))
+			for i, count := range counts {
+				statuses[i] = statusCount{
+					Status: count.Status,
+					Name:   crawler_model.UrlFrontierStatusName(count.Status),
+					Total:  count.Total,
+				}
+			}
+
+			if *asJson {
+				return json.NewEncoder(os.Stdout).Encode(statuses)
+			}
+
+			var total int64
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "STATUS\tNAME\tTOTAL")
+			for _, status := range statuses {
+				fmt.Fprintf(w, "%d\t%s\t%d\n", status.Status, status.Name, status.Total)
+				total += status.Total
+			}
+			fmt.Fprintf(w, "\tall\t%d\n", total)
+			return w.Flush()
+		},
+	}
+}
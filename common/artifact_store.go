@@ -0,0 +1,44 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ArtifactStore keeps the artifacts downloaded by the scrapper, the judgement PDFs and raw pages.
+type ArtifactStore interface {
+	// Upload streams r to objectPath and reports what was written.
+	// When reading r fails the upload is aborted, no partial artifact is left behind.
+	Upload(ctx context.Context, objectPath string, r io.Reader, attrs ArtifactAttrs) (StoredArtifact, error)
+	// Open reads back the artifact behind a link returned by Upload.
+	Open(ctx context.Context, link string) (io.ReadCloser, error)
+}
+
+// ArtifactAttrs are set on an uploaded artifact.
+type ArtifactAttrs struct {
+	ContentType string
+	// Metadata is kept as custom metadata of the object, by the stores supporting it
+	Metadata map[string]string
+}
+
+// StoredArtifact is an artifact as reported by its store once written.
+type StoredArtifact struct {
+	// Link is stored on the extraction
+	Link string
+	Size int64
+	// Md5 is the digest computed by the store, nil when the store does not report one
+	Md5 []byte
+}
+
+var (
+	Artifacts ArtifactStore
+)
+
+func SetArtifactStore(newStore ArtifactStore) error {
+	if newStore == nil {
+		return errors.New("cannot assign nil artifact store")
+	}
+	Artifacts = newStore
+	return nil
+}
@@ -1,14 +1,41 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	CRAWLER_NAME   string = "singapore-supreme-court-crawler"
 	CRAWLER_DOMAIN string = "www.elitigation.sg"
+	LISTING_PATH   string = "/gd/Home/Index"
 	GCS_BUCKET     string = "lexicon-bo-bucket"
+
+	DEFAULT_LEASE_DURATION time.Duration = 30 * time.Minute
+
+	// Browser pages, and listing pages or url frontiers handled at once
+	DEFAULT_CRAWL_CONCURRENCY  int = 7
+	DEFAULT_SCRAPE_CONCURRENCY int = 10
+)
+
+const (
+	NATS_STREAM              string        = "SINGAPORE_SUPREME_COURT_CRAWLER"
+	NATS_CRAWLER_CONSUMER    string        = "crawler"
+	NATS_SCRAPPER_CONSUMER   string        = "scrapper"
+	NATS_ACK_WAIT            time.Duration = 10 * time.Minute
+	NATS_DUPLICATE_WINDOW    time.Duration = 24 * time.Hour
+	NATS_DEFAULT_MAX_DELIVER int           = 5
+
+	// Scrape workers publish the url frontiers due again this often
+	NATS_DEFAULT_REQUEUE_INTERVAL time.Duration = 5 * time.Minute
 )
 
 var (
 	GCS_FOLDER      string = fmt.Sprintf("%s/%s", CRAWLER_NAME, "judgements")
 	GCS_HTML_FOLDER string = fmt.Sprintf("%s/%s", CRAWLER_NAME, "html")
+
+	// Listing page urls consumed by the crawler
+	NATS_LISTING_SUBJECT string = fmt.Sprintf("%s.listings", CRAWLER_NAME)
+	// Url frontier ids consumed by the scrapper
+	NATS_URL_FRONTIER_SUBJECT string = fmt.Sprintf("%s.url_frontiers", CRAWLER_NAME)
 )
@@ -4,14 +4,12 @@ import (
 	"errors"
 	"lexicon/singapore-supreme-court-crawler/repository"
 
-	"cloud.google.com/go/storage"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	Pool          *pgxpool.Pool
-	StorageClient *storage.Client
-	Query         *repository.Queries
+	Pool  *pgxpool.Pool
+	Query *repository.Queries
 )
 
 func SetDatabase(newPool *pgxpool.Pool) error {
@@ -23,14 +21,6 @@ func SetDatabase(newPool *pgxpool.Pool) error {
 	return nil
 }
 
-func SetStorageClient(newClient *storage.Client) error {
-	if newClient == nil {
-		return errors.New("cannot assign nil storage client")
-	}
-	StorageClient = newClient
-	return nil
-}
-
 func SetQueries(newQueries *repository.Queries) error {
 	if newQueries == nil {
 		return errors.New("cannot assign nil queries")
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// RequestLimiter paces the requests made to elitigation.sg, shared by the browser pages and the HTTP downloads.
+type RequestLimiter interface {
+	// Wait blocks until url may be requested, it fails when url is disallowed by robots.txt.
+	Wait(ctx context.Context, url string) error
+}
+
+var (
+	Limiter RequestLimiter
+	// HttpClient downloads the judgement artifacts
+	HttpClient = http.DefaultClient
+)
+
+func SetRequestLimiter(newLimiter RequestLimiter) error {
+	if newLimiter == nil {
+		return errors.New("cannot assign nil request limiter")
+	}
+	Limiter = newLimiter
+	return nil
+}
+
+func SetHttpClient(newClient *http.Client) error {
+	if newClient == nil {
+		return errors.New("cannot assign nil http client")
+	}
+	HttpClient = newClient
+	return nil
+}
+
+// WaitForRequest waits for the turn of url on Limiter, requests are not paced when it is not set.
+func WaitForRequest(ctx context.Context, url string) error {
+	if Limiter == nil {
+		return nil
+	}
+	return Limiter.Wait(ctx, url)
+}
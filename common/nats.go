@@ -0,0 +1,20 @@
+package common
+
+import (
+	"errors"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var (
+	// JetStream is nil when the crawler runs without a work queue
+	JetStream jetstream.JetStream
+)
+
+func SetJetStream(newJetStream jetstream.JetStream) error {
+	if newJetStream == nil {
+		return errors.New("cannot assign nil jetstream")
+	}
+	JetStream = newJetStream
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package natstest runs an in-process NATS server with JetStream for the tests of the work queue.
+package natstest
+
+import (
+	"context"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Start starts a NATS server with JetStream storing under a temporary directory and registers a connection to it in common.
+// The connection and the server are shut down, and common.JetStream restored, when the test ends.
+func Start(t testing.TB) (*nats.Conn, jetstream.JetStream) {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		srv.Shutdown()
+		t.Fatal("nats server is not ready for connections")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		srv.Shutdown()
+		t.Fatalf("failed to connect to nats server: %v", err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		srv.Shutdown()
+		t.Fatalf("failed to create jetstream: %v", err)
+	}
+
+	previous := common.JetStream
+	if err := common.SetJetStream(js); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		common.JetStream = previous
+		nc.Close()
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+
+	return nc, js
+}
+
+// Outcomes of a delivered message reported by Watcher.
+const (
+	ACKED      = "ack"
+	NAKED      = "nak"
+	TERMINATED = "term"
+)
+
+// Watcher reports how the messages delivered to a consumer are settled, from the advisories of the server and the acks pending.
+type Watcher struct {
+	consumer   jetstream.Consumer
+	advisories *nats.Subscription
+}
+
+// Watch subscribes to the advisories of consumer on nc.
+func Watch(t testing.TB, nc *nats.Conn, consumer jetstream.Consumer) *Watcher {
+	t.Helper()
+
+	info := consumer.CachedInfo()
+	advisories, err := nc.SubscribeSync(fmt.Sprintf("$JS.EVENT.ADVISORY.CONSUMER.*.%s.%s", info.Stream, info.Name))
+	if err != nil {
+		t.Fatalf("failed to subscribe to the advisories of consumer %s: %v", info.Name, err)
+	}
+	t.Cleanup(func() { advisories.Unsubscribe() })
+
+	return &Watcher{consumer: consumer, advisories: advisories}
+}
+
+// Outcome waits for the message last delivered to the consumer to be settled and returns ACKED, NAKED or TERMINATED.
+func (w *Watcher) Outcome(t testing.TB) string {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if outcome := w.advisory(100 * time.Millisecond); outcome != "" {
+			return outcome
+		}
+
+		info, err := w.consumer.Info(context.Background())
+		if err != nil {
+			t.Fatalf("failed to get consumer info: %v", err)
+		}
+		if info.NumAckPending == 0 {
+			// A terminated message is no longer pending either, its advisory may still be on its way
+			if outcome := w.advisory(100 * time.Millisecond); outcome != "" {
+				return outcome
+			}
+			return ACKED
+		}
+	}
+
+	t.Fatal("message was not settled")
+	return ""
+}
+
+func (w *Watcher) advisory(wait time.Duration) string {
+	for {
+		m, err := w.advisories.NextMsg(wait)
+		if err != nil {
+			return ""
+		}
+		switch {
+		case strings.Contains(m.Subject, ".MSG_NAKED."):
+			return NAKED
+		case strings.Contains(m.Subject, ".MSG_TERMINATED."):
+			return TERMINATED
+		}
+	}
+}
+
+// Next returns the next message delivered to consumer.
+func Next(t testing.TB, consumer jetstream.Consumer) jetstream.Msg {
+	t.Helper()
+
+	m, err := consumer.Next(jetstream.FetchMaxWait(5 * time.Second))
+	if err != nil {
+		t.Fatalf("no message delivered: %v", err)
+	}
+	return m
+}
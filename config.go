@@ -2,8 +2,14 @@ package main
 
 import (
 	"fmt"
+	"lexicon/singapore-supreme-court-crawler/common"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
 func loadEnvString(key string, result *string) {
@@ -15,6 +21,32 @@ func loadEnvString(key string, result *string) {
 	*result = s
 }
 
+func loadEnvDuration(key string, result *time.Duration) {
+	s, ok := os.LookupEnv(key)
+
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return
+	}
+	*result = d
+}
+
+func loadEnvBool(key string, result *bool) {
+	s, ok := os.LookupEnv(key)
+
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return
+	}
+	*result = b
+}
+
 func loadEnvUint(key string, result *uint) {
 	s, ok := os.LookupEnv(key)
 
@@ -87,16 +119,230 @@ func (l *listenConfig) loadFromEnv() {
 	loadEnvUint("LISTEN_PORT", &l.Port)
 }
 
+/* Crawl Configuration */
+
+type crawlConfig struct {
+	// JobsFile is a JSON array of crawler.CrawlJob, the default corruption search when empty
+	JobsFile string `json:"jobs_file"`
+	// Concurrency is the number of listing pages crawled at once
+	Concurrency uint `json:"concurrency"`
+}
+
+func defaultCrawlConfig() crawlConfig {
+	return crawlConfig{
+		JobsFile:    "",
+		Concurrency: uint(common.DEFAULT_CRAWL_CONCURRENCY),
+	}
+}
+
+func (c *crawlConfig) loadFromEnv() {
+	loadEnvString("CRAWL_JOBS_FILE", &c.JobsFile)
+	loadEnvUint("CRAWL_CONCURRENCY", &c.Concurrency)
+}
+
+/* Scrape Configuration */
+
+type scrapeConfig struct {
+	// MaxAttempts is the number of failed scrapes before a url frontier is dead-lettered
+	MaxAttempts    uint          `json:"max_attempts"`
+	RetryBaseDelay time.Duration `json:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `json:"retry_max_delay"`
+	// LeaseDuration is how long a leased url frontier stays hidden from other scrappers
+	LeaseDuration time.Duration `json:"lease_duration"`
+	// Concurrency is the number of url frontiers scraped at once
+	Concurrency uint `json:"concurrency"`
+}
+
+func defaultScrapeConfig() scrapeConfig {
+	policy := crawler_model.DefaultRetryPolicy()
+	return scrapeConfig{
+		MaxAttempts:    uint(policy.MaxAttempts),
+		RetryBaseDelay: policy.BaseDelay,
+		RetryMaxDelay:  policy.MaxDelay,
+		LeaseDuration:  common.DEFAULT_LEASE_DURATION,
+		Concurrency:    uint(common.DEFAULT_SCRAPE_CONCURRENCY),
+	}
+}
+
+func (s *scrapeConfig) loadFromEnv() {
+	loadEnvUint("SCRAPE_MAX_ATTEMPTS", &s.MaxAttempts)
+	loadEnvDuration("SCRAPE_RETRY_BASE_DELAY", &s.RetryBaseDelay)
+	loadEnvDuration("SCRAPE_RETRY_MAX_DELAY", &s.RetryMaxDelay)
+	loadEnvDuration("SCRAPE_LEASE_DURATION", &s.LeaseDuration)
+	loadEnvUint("SCRAPE_CONCURRENCY", &s.Concurrency)
+}
+
+func (s scrapeConfig) RetryPolicy() crawler_model.RetryPolicy {
+	return crawler_model.RetryPolicy{
+		MaxAttempts: int32(s.MaxAttempts),
+		BaseDelay:   s.RetryBaseDelay,
+		MaxDelay:    s.RetryMaxDelay,
+	}
+}
+
+/* NATS Configuration */
+
+type natsConfig struct {
+	// Url enables the JetStream work queue when set
+	Url        string `json:"url"`
+	MaxDeliver uint   `json:"max_deliver"`
+	// RequeueInterval is how often scrape workers publish the url frontiers due again
+	RequeueInterval time.Duration `json:"requeue_interval"`
+}
+
+func defaultNatsConfig() natsConfig {
+	return natsConfig{
+		Url:             "",
+		MaxDeliver:      uint(common.NATS_DEFAULT_MAX_DELIVER),
+		RequeueInterval: common.NATS_DEFAULT_REQUEUE_INTERVAL,
+	}
+}
+
+func (n *natsConfig) loadFromEnv() {
+	loadEnvString("NATS_URL", &n.Url)
+	loadEnvUint("NATS_MAX_DELIVER", &n.MaxDeliver)
+	loadEnvDuration("NATS_REQUEUE_INTERVAL", &n.RequeueInterval)
+}
+
+/* Fetch Configuration */
+
+const (
+	FETCHER_BROWSER = "browser"
+	FETCHER_HTTP    = "http"
+)
+
+type fetchConfig struct {
+	// Fetcher downloads the pages: browser for the headless Chrome, http for plain HTTP requests
+	Fetcher   string        `json:"fetcher"`
+	UserAgent string        `json:"user_agent"`
+	Timeout   time.Duration `json:"timeout"`
+	// Interval is the delay between two requests to the site, shared by every page and download of the process
+	Interval time.Duration `json:"interval"`
+	Burst    uint          `json:"burst"`
+	// Robots skips the urls disallowed by the site's robots.txt
+	Robots bool `json:"robots"`
+	// RetryAttempts is the number of tries of a page load, download or upload failing with a transient error
+	RetryAttempts  uint          `json:"retry_attempts"`
+	RetryBaseDelay time.Duration `json:"retry_base_delay"`
+	RetryMaxDelay  time.Duration `json:"retry_max_delay"`
+}
+
+func defaultFetchConfig() fetchConfig {
+	policy := retry.DefaultPolicy()
+	return fetchConfig{
+		Fetcher:   FETCHER_BROWSER,
+		UserAgent: fmt.Sprintf("Mozilla/5.0 (compatible; %s/1.0; archives the published judgements of the Supreme Court of Singapore)", common.CRAWLER_NAME),
+		Timeout:   time.Minute,
+		Interval:  500 * time.Millisecond,
+		Burst:     1,
+		Robots:    true,
+
+		RetryAttempts:  uint(policy.MaxAttempts),
+		RetryBaseDelay: policy.BaseDelay,
+		RetryMaxDelay:  policy.MaxDelay,
+	}
+}
+
+func (f *fetchConfig) loadFromEnv() {
+	loadEnvString("FETCHER", &f.Fetcher)
+	loadEnvString("FETCHER_USER_AGENT", &f.UserAgent)
+	loadEnvDuration("FETCHER_TIMEOUT", &f.Timeout)
+	loadEnvDuration("FETCHER_INTERVAL", &f.Interval)
+	loadEnvUint("FETCHER_BURST", &f.Burst)
+	loadEnvBool("FETCHER_ROBOTS", &f.Robots)
+	loadEnvUint("FETCHER_RETRY_ATTEMPTS", &f.RetryAttempts)
+	loadEnvDuration("FETCHER_RETRY_BASE_DELAY", &f.RetryBaseDelay)
+	loadEnvDuration("FETCHER_RETRY_MAX_DELAY", &f.RetryMaxDelay)
+}
+
+func (f fetchConfig) RetryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts: int(f.RetryAttempts),
+		BaseDelay:   f.RetryBaseDelay,
+		MaxDelay:    f.RetryMaxDelay,
+	}
+}
+
+// HttpFetcher returns the fetcher of the http mode downloading with client, nil in browser mode.
+func (f fetchConfig) HttpFetcher(client *http.Client) (*document.HttpFetcher, error) {
+	switch f.Fetcher {
+	case FETCHER_BROWSER:
+		return nil, nil
+	case FETCHER_HTTP:
+		return document.NewHttpFetcher(client), nil
+	default:
+		return nil, fmt.Errorf("unknown fetcher %q, expected browser or http", f.Fetcher)
+	}
+}
+
+/* Storage Configuration */
+
+const (
+	STORAGE_BACKEND_GCS   = "gcs"
+	STORAGE_BACKEND_LOCAL = "local"
+	STORAGE_BACKEND_S3    = "s3"
+)
+
+type storageConfig struct {
+	// Backend is where artifacts are uploaded: gcs, local or s3
+	Backend   string `json:"backend"`
+	GcsBucket string `json:"gcs_bucket"`
+	// LocalDir is the directory of the local backend
+	LocalDir          string `json:"local_dir"`
+	S3Endpoint        string `json:"s3_endpoint"`
+	S3Region          string `json:"s3_region"`
+	S3Bucket          string `json:"s3_bucket"`
+	S3AccessKeyId     string `json:"s3_access_key_id"`
+	S3SecretAccessKey string `json:"s3_secret_access_key"`
+	S3UseSsl          bool   `json:"s3_use_ssl"`
+}
+
+func defaultStorageConfig() storageConfig {
+	return storageConfig{
+		Backend:           STORAGE_BACKEND_GCS,
+		GcsBucket:         common.GCS_BUCKET,
+		LocalDir:          "artifacts",
+		S3Endpoint:        "",
+		S3Region:          "",
+		S3Bucket:          common.GCS_BUCKET,
+		S3AccessKeyId:     "",
+		S3SecretAccessKey: "",
+		S3UseSsl:          true,
+	}
+}
+
+func (s *storageConfig) loadFromEnv() {
+	loadEnvString("STORAGE_BACKEND", &s.Backend)
+	loadEnvString("GCS_BUCKET_NAME", &s.GcsBucket)
+	loadEnvString("STORAGE_LOCAL_DIR", &s.LocalDir)
+	loadEnvString("S3_ENDPOINT", &s.S3Endpoint)
+	loadEnvString("S3_REGION", &s.S3Region)
+	loadEnvString("S3_BUCKET", &s.S3Bucket)
+	loadEnvString("S3_ACCESS_KEY_ID", &s.S3AccessKeyId)
+	loadEnvString("S3_SECRET_ACCESS_KEY", &s.S3SecretAccessKey)
+	loadEnvBool("S3_USE_SSL", &s.S3UseSsl)
+}
+
 type config struct {
-	Listen        listenConfig `json:"listen"`
-	PgSql         pgSqlConfig  `json:"pgsql"`
-	BackendApiKey string       `json:"api_key"`
-	ServerSalt    string       `json:"salt"`
+	Listen        listenConfig  `json:"listen"`
+	PgSql         pgSqlConfig   `json:"pgsql"`
+	Crawl         crawlConfig   `json:"crawl"`
+	Scrape        scrapeConfig  `json:"scrape"`
+	Nats          natsConfig    `json:"nats"`
+	Fetch         fetchConfig   `json:"fetch"`
+	Storage       storageConfig `json:"storage"`
+	BackendApiKey string        `json:"api_key"`
+	ServerSalt    string        `json:"salt"`
 }
 
 func (c *config) loadFromEnv() {
 	c.Listen.loadFromEnv()
 	c.PgSql.loadFromEnv()
+	c.Crawl.loadFromEnv()
+	c.Scrape.loadFromEnv()
+	c.Nats.loadFromEnv()
+	c.Fetch.loadFromEnv()
+	c.Storage.loadFromEnv()
 	loadEnvString("API_KEY", &c.BackendApiKey)
 	loadEnvString("SALT", &c.ServerSalt)
 }
@@ -105,6 +351,11 @@ func defaultConfig() config {
 	return config{
 		Listen:        defaultListenConfig(),
 		PgSql:         defaultPgSql(),
+		Crawl:         defaultCrawlConfig(),
+		Scrape:        defaultScrapeConfig(),
+		Nats:          defaultNatsConfig(),
+		Fetch:         defaultFetchConfig(),
+		Storage:       defaultStorageConfig(),
 		BackendApiKey: "",
 		ServerSalt:    "",
 	}
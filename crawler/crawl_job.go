@@ -0,0 +1,127 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"os"
+	"strconv"
+
+	stdUrl "net/url"
+)
+
+// CrawlJob is one search on the elitigation.sg listing whose result pages are crawled.
+type CrawlJob struct {
+	Name           string `json:"name"`
+	Filter         string `json:"filter"`
+	YearOfDecision string `json:"year_of_decision"`
+	SearchPhrase   string `json:"search_phrase"`
+	SortBy         string `json:"sort_by"`
+	SortAscending  bool   `json:"sort_ascending"`
+}
+
+func DefaultCrawlJob() CrawlJob {
+	return CrawlJob{
+		Name:           "corruption",
+		Filter:         "SUPCT",
+		YearOfDecision: "All",
+		SearchPhrase:   "CatchWords:Corruption",
+		SortBy:         "DateOfDecision",
+		SortAscending:  false,
+	}
+}
+
+// LoadCrawlJobs reads a JSON array of crawl jobs from path.
+// Fields left empty fall back to the values of DefaultCrawlJob, except the search phrase.
+func LoadCrawlJobs(path string) ([]CrawlJob, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crawl jobs: %w", err)
+	}
+
+	var jobs []CrawlJob
+	if err := json.Unmarshal(content, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse crawl jobs %s: %w", path, err)
+	}
+
+	for i := range jobs {
+		jobs[i] = jobs[i].WithDefaults()
+		if err := jobs[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid crawl job #%d in %s: %w", i+1, path, err)
+		}
+	}
+
+	return jobs, nil
+}
+
+// WithDefaults fills the empty listing parameters of the job.
+func (j CrawlJob) WithDefaults() CrawlJob {
+	defaults := DefaultCrawlJob()
+	if j.Filter == "" {
+		j.Filter = defaults.Filter
+	}
+	if j.YearOfDecision == "" {
+		j.YearOfDecision = defaults.YearOfDecision
+	}
+	if j.SortBy == "" {
+		j.SortBy = defaults.SortBy
+	}
+	if j.Name == "" {
+		j.Name = j.SearchPhrase
+	}
+	return j
+}
+
+func (j CrawlJob) Validate() error {
+	if j.SearchPhrase == "" {
+		return errors.New("search phrase is empty")
+	}
+	if j.Filter == "" {
+		return errors.New("filter is empty")
+	}
+	if j.YearOfDecision == "" {
+		return errors.New("year of decision is empty")
+	}
+	if j.SortBy == "" {
+		return errors.New("sort by is empty")
+	}
+	return nil
+}
+
+func (j CrawlJob) urlCrawler() urlCrawler {
+	return urlCrawler{
+		BaseUrl:        fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, common.LISTING_PATH),
+		Filter:         j.Filter,
+		YearOfDecision: j.YearOfDecision,
+		SortBy:         j.SortBy,
+		CurrentPage:    1,
+		SortAscending:  j.SortAscending,
+		SearchPhrase:   j.SearchPhrase,
+		Verbose:        false,
+	}
+}
+
+// StartUrl returns the first listing page of the job.
+func (j CrawlJob) StartUrl() string {
+	u := j.urlCrawler()
+	return u.constructUrl()
+}
+
+// Key identifies the listing searched by the job, independent of its name.
+// Jobs with the same key share a crawl watermark.
+func (j CrawlJob) Key() string {
+	params := stdUrl.Values{}
+	params.Set("filter", j.Filter)
+	params.Set("yearOfDecision", j.YearOfDecision)
+	params.Set("searchPhrase", j.SearchPhrase)
+	params.Set("sortBy", j.SortBy)
+	params.Set("sortAscending", strconv.FormatBool(j.SortAscending))
+	return params.Encode()
+}
+
+// isNewestFirst reports whether the listing of the job starts with the latest decisions,
+// which incremental crawling relies on to stop early.
+func (j CrawlJob) isNewestFirst() bool {
+	return j.SortBy == "DateOfDecision" && !j.SortAscending
+}
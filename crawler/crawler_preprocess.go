@@ -1,55 +1,72 @@
 package crawler
 
 import (
+	"lexicon/singapore-supreme-court-crawler/document"
 	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
 	"github.com/rs/zerolog/log"
 )
 
-func getTitle(e *rod.Element) (string, error) {
-	title, err := e.Element("a.h5.gd-heardertext")
+func getTitle(e document.Node) (string, error) {
+	title, err := e.Find("a.h5.gd-heardertext")
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting title")
 		return "", err
 	}
 
-	titleText := title.MustText()
+	titleText, err := title.Text()
+	if err != nil {
+		return "", err
+	}
 	return strings.TrimSpace(titleText), nil
 }
 
-func getCaseNumbers(e *rod.Element) ([]string, error) {
-	caseNumbers, err := e.Elements("a.case-num-link")
+func getCaseNumbers(e document.Node) ([]string, error) {
+	caseNumbers, err := e.FindAll("a.case-num-link")
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting case numbers")
 		return []string{}, err
 	}
 
-	if len(caseNumbers) == 0 {
-		return []string{}, nil
-	}
-
 	numbers := make([]string, len(caseNumbers))
 	for i, number := range caseNumbers {
-		numbers[i] = strings.TrimSpace(number.MustText())
+		text, err := number.Text()
+		if err != nil {
+			return []string{}, err
+		}
+		numbers[i] = strings.TrimSpace(text)
 	}
 
 	return numbers, nil
 }
 
-func getCitationNumber(e *rod.Element) (string, error) {
-	citationNumber, err := e.Element("a.citation-num-link")
+func getCitationNumber(e document.Node) (string, error) {
+	citationNumber, err := e.Find("a.citation-num-link")
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting citation number")
 		return "", err
 	}
 
-	return strings.TrimSpace(strings.ReplaceAll(citationNumber.MustText(), "|", "")), nil
+	text, err := citationNumber.Text()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.ReplaceAll(text, "|", "")), nil
 }
 
-func getDecisionDate(e *rod.Element) (string, error) {
-	stringDate := strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(e.MustElement("a.decision-date-link").MustText(), "Decision Date:", ""), "|", ""))
+func getDecisionDate(e document.Node) (string, error) {
+	decisionDateLink, err := e.Find("a.decision-date-link")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting decision date")
+		return "", err
+	}
+	text, err := decisionDateLink.Text()
+	if err != nil {
+		return "", err
+	}
+
+	stringDate := strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(text, "Decision Date:", ""), "|", ""))
 	decisionDate, err := time.Parse("2 Jan 2006", stringDate)
 	if err != nil {
 		log.Error().Err(err).Msg("Error parsing decision date")
@@ -58,20 +75,20 @@ func getDecisionDate(e *rod.Element) (string, error) {
 	return decisionDate.Format(time.RFC3339), nil
 }
 
-func getCategories(e *rod.Element) ([]string, error) {
-	categories, err := e.Elements("div.gd-catchword-container > a")
+func getCategories(e document.Node) ([]string, error) {
+	categories, err := e.FindAll("div.gd-catchword-container > a")
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting categories")
 		return []string{}, err
 	}
 
-	if len(categories) == 0 {
-		return []string{}, nil
-	}
-
 	names := make([]string, len(categories))
 	for i, category := range categories {
-		names[i] = strings.TrimSpace(removeBrackets(category.MustText()))
+		text, err := category.Text()
+		if err != nil {
+			return []string{}, err
+		}
+		names[i] = strings.TrimSpace(removeBrackets(text))
 	}
 
 	return names, nil
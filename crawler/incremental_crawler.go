@@ -0,0 +1,137 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// crawlJobIncremental crawls the listing pages of job one by one, newest first, and stops
+// at the first page that holds only known judgements or decisions older than the watermark of the job.
+// The watermark is only saved by a complete run, see incrementalStop.
+func (c *CrawlerImpl) crawlJobIncremental(ctx context.Context, job CrawlJob) error {
+	since, err := services.GetCrawlWatermark(ctx, job.Key())
+	if err != nil {
+		return fmt.Errorf("failed to get watermark of job %s: %w", job.Name, err)
+	}
+	if since != nil {
+		log.Info().Msgf("Crawling job %s incrementally since %s", job.Name, since.Format(time.DateOnly))
+	}
+
+	urlList, err := c.listingUrls(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	watermark := newWatermarkTracker()
+	for i, url := range urlList {
+		urlFrontiers, err := c.fetchListingPage(ctx, url)
+		if err != nil {
+			return fmt.Errorf("error crawling %s: %w", url, err)
+		}
+		if len(urlFrontiers) == 0 {
+			break
+		}
+
+		knownIds, err := services.GetKnownUrlFrontierIds(ctx, lo.Map(urlFrontiers, func(u repository.UrlFrontier, _ int) string {
+			return u.ID
+		}))
+		if err != nil {
+			return err
+		}
+
+		if err := storeUrlFrontiers(ctx, urlFrontiers); err != nil {
+			return err
+		}
+		watermark.observe(urlFrontiers)
+
+		if reason := incrementalStop(urlFrontiers, len(knownIds), since); reason != "" {
+			log.Info().Msgf("Page %d of %d of job %s %s, stopping", i+1, len(urlList), job.Name, reason)
+			break
+		}
+	}
+
+	return watermark.save(ctx, job)
+}
+
+// incrementalStop returns why an incremental crawl stops after a page of urlFrontiers, known of which were already stored,
+// or "" to crawl the next page. since is the watermark of the last complete run, nil when no run completed.
+//
+// Known judgements only stop the crawl when none of them is newer than the watermark: a run that failed
+// part way stored the first pages without saving a watermark, and the pages it did not reach must still be crawled.
+func incrementalStop(urlFrontiers []repository.UrlFrontier, known int, since *time.Time) string {
+	if since == nil {
+		return ""
+	}
+	if allDecidedBefore(urlFrontiers, *since) {
+		return "is older than the watermark"
+	}
+	if known == len(urlFrontiers) && noneDecidedAfter(urlFrontiers, *since) {
+		return "only holds known judgements"
+	}
+	return ""
+}
+
+// allDecidedBefore reports whether every url frontier has a decision date strictly before t.
+// A url frontier without a readable decision date is never considered older.
+func allDecidedBefore(urlFrontiers []repository.UrlFrontier, t time.Time) bool {
+	return lo.EveryBy(urlFrontiers, func(u repository.UrlFrontier) bool {
+		decisionDate, err := time.Parse(time.RFC3339, u.Metadata.DecisionDate)
+		return err == nil && decisionDate.Before(t)
+	})
+}
+
+// noneDecidedAfter reports whether every url frontier has a decision date at or before t.
+// A url frontier without a readable decision date is considered newer.
+func noneDecidedAfter(urlFrontiers []repository.UrlFrontier, t time.Time) bool {
+	return lo.EveryBy(urlFrontiers, func(u repository.UrlFrontier) bool {
+		decisionDate, err := time.Parse(time.RFC3339, u.Metadata.DecisionDate)
+		return err == nil && !decisionDate.After(t)
+	})
+}
+
+// watermarkTracker keeps the latest decision date of the url frontiers crawled for a job.
+// It is safe for concurrent use.
+type watermarkTracker struct {
+	mu     sync.Mutex
+	latest time.Time
+}
+
+func newWatermarkTracker() *watermarkTracker {
+	return &watermarkTracker{}
+}
+
+func (w *watermarkTracker) observe(urlFrontiers []repository.UrlFrontier) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, u := range urlFrontiers {
+		decisionDate, err := time.Parse(time.RFC3339, u.Metadata.DecisionDate)
+		if err != nil {
+			continue
+		}
+		if decisionDate.After(w.latest) {
+			w.latest = decisionDate
+		}
+	}
+}
+
+// save persists the latest decision date as the watermark of job, nothing is saved when no date was seen.
+func (w *watermarkTracker) save(ctx context.Context, job CrawlJob) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.latest.IsZero() {
+		return nil
+	}
+	if err := services.SaveCrawlWatermark(ctx, job.Key(), w.latest); err != nil {
+		return fmt.Errorf("failed to save watermark of job %s: %w", job.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"testing"
+	"time"
+)
+
+func TestIncrementalStop(t *testing.T) {
+	page := func(decisionDates ...string) []repository.UrlFrontier {
+		urlFrontiers := make([]repository.UrlFrontier, len(decisionDates))
+		for i, decisionDate := range decisionDates {
+			urlFrontiers[i].Metadata = models.UrlFrontierMetadata{DecisionDate: decisionDate}
+		}
+		return urlFrontiers
+	}
+	watermark, _ := time.Parse(time.RFC3339, "2024-03-01T00:00:00Z")
+
+	tests := []struct {
+		name         string
+		urlFrontiers []repository.UrlFrontier
+		known        int
+		since        *time.Time
+		wantStop     bool
+	}{
+		{
+			name:         "known judgements without a complete run",
+			urlFrontiers: page("2024-03-05T00:00:00Z", "2024-02-20T00:00:00Z"),
+			known:        2,
+		},
+		{
+			name:         "known judgements up to the watermark",
+			urlFrontiers: page("2024-03-01T00:00:00Z", "2024-02-20T00:00:00Z"),
+			known:        2,
+			since:        &watermark,
+			wantStop:     true,
+		},
+		{
+			name:         "known judgements newer than the watermark, stored by a failed run",
+			urlFrontiers: page("2024-03-05T00:00:00Z", "2024-02-20T00:00:00Z"),
+			known:        2,
+			since:        &watermark,
+		},
+		{
+			name:         "known judgements without a decision date",
+			urlFrontiers: page("", "2024-02-20T00:00:00Z"),
+			known:        2,
+			since:        &watermark,
+		},
+		{
+			name:         "a new judgement up to the watermark",
+			urlFrontiers: page("2024-03-01T00:00:00Z", "2024-02-20T00:00:00Z"),
+			known:        1,
+			since:        &watermark,
+		},
+		{
+			name:         "new judgements older than the watermark",
+			urlFrontiers: page("2024-02-28T00:00:00Z", "2024-02-20T00:00:00Z"),
+			since:        &watermark,
+			wantStop:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := incrementalStop(tt.urlFrontiers, tt.known, tt.since); (got != "") != tt.wantStop {
+				t.Errorf("incrementalStop() = %q, want stop %v", got, tt.wantStop)
+			}
+		})
+	}
+}
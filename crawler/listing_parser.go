@@ -0,0 +1,159 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/citation"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"regexp"
+	"strconv"
+
+	stdUrl "net/url"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// parseListingPage reads the judgement cards of a listing page.
+// Cards that cannot be read are logged and skipped.
+func parseListingPage(doc document.Node) ([]repository.UrlFrontier, error) {
+	cards, err := doc.FindAll("#listview > div.row > div.card.col-12")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting elements")
+		return nil, err
+	}
+
+	log.Info().Msgf("Found %d elements", len(cards))
+
+	detailUrls := []repository.UrlFrontier{}
+	for _, card := range cards {
+		crawlerResult, err := getElementContent(card)
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting element content")
+			continue
+		}
+
+		detailUrls = append(detailUrls, crawlerResult)
+		log.Info().Msg("Crawler result: " + crawlerResult.Url)
+	}
+
+	return detailUrls, nil
+}
+
+func getElementContent(element document.Node) (repository.UrlFrontier, error) {
+	titleLink, err := element.Find("a.h5.gd-heardertext")
+	if err != nil {
+		return repository.UrlFrontier{}, err
+	}
+	link, ok, err := titleLink.Attr("href")
+	if err != nil {
+		return repository.UrlFrontier{}, err
+	}
+	if !ok {
+		return repository.UrlFrontier{}, errors.New("link is empty")
+	}
+	if !isDetailPage(link) {
+		return repository.UrlFrontier{}, errors.New("link is not a detail page")
+	}
+
+	title, err := getTitle(element)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting title")
+		return repository.UrlFrontier{}, err
+	}
+	caseNumbers, err := getCaseNumbers(element)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting case numbers")
+		return repository.UrlFrontier{}, err
+	}
+	citationNumber, err := getCitationNumber(element)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting citation number")
+		return repository.UrlFrontier{}, err
+	}
+	var parsedCitation *citation.Citation
+	if parsed, err := citation.Parse(citationNumber); err == nil {
+		citationNumber, parsedCitation = parsed.String(), &parsed
+	} else {
+		log.Warn().Err(err).Msgf("Keeping citation number of %s as written", link)
+	}
+	categories, err := getCategories(element)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting categories")
+		return repository.UrlFrontier{}, err
+	}
+
+	decisionDate, err := getDecisionDate(element)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting decision date")
+		return repository.UrlFrontier{}, err
+	}
+	detailUrls := repository.UrlFrontier{
+		Url: fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, link),
+		Metadata: models.UrlFrontierMetadata{
+			Title:          title,
+			CaseNumbers:    caseNumbers,
+			CitationNumber: citationNumber,
+			Citation:       parsedCitation,
+			DecisionDate:   decisionDate,
+			Categories:     categories,
+		},
+	}
+
+	return detailUrls, nil
+}
+
+// parseLastPage reads the last page number and the total number of results of a listing page.
+func parseLastPage(doc document.Node) (lo.Tuple2[int, int], error) {
+	totalResultElement, err := doc.Find("#listview > div.row.justify-content-between.align-items-center > div.gd-csummary")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting total result element")
+		return lo.Tuple2[int, int]{}, err
+	}
+	res, err := totalResultElement.Text()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting total result element")
+		return lo.Tuple2[int, int]{}, err
+	}
+	totalResult, err := strconv.Atoi(regexp.MustCompile(`\d+`).FindString(res))
+	if err != nil {
+		log.Error().Err(err).Msg("Error converting total result to integer")
+		return lo.Tuple2[int, int]{}, retry.Parse(err)
+	}
+
+	elements, err := doc.FindAll("#listview > div.row.justify-content-end > div > ul > li.page-item.page-link> a")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting elements")
+		return lo.Tuple2[int, int]{}, err
+	}
+
+	lastPage := 0
+	for _, element := range elements {
+		href, ok, err := element.Attr("href")
+		if err != nil || !ok {
+			continue
+		}
+		u, err := stdUrl.Parse(href)
+		if err != nil {
+			log.Error().Err(err).Msg("Error parsing URL")
+			continue
+		}
+		lpInt, err := strconv.Atoi(u.Query().Get("CurrentPage"))
+		if err != nil {
+			log.Error().Err(err).Msg("Error converting LP to integer")
+			continue
+		}
+		if lastPage < lpInt {
+			lastPage = lpInt
+		}
+	}
+
+	return lo.Tuple2[int, int]{
+		A: lastPage,
+		B: totalResult,
+	}, nil
+}
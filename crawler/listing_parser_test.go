@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"context"
+	"lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/document/documenttest"
+	"testing"
+)
+
+type listingResult struct {
+	Url      string                     `json:"url"`
+	Metadata models.UrlFrontierMetadata `json:"metadata"`
+}
+
+func TestFetchListingPage(t *testing.T) {
+	documenttest.Record(t, "testdata", "listing.html", DefaultCrawlJob().StartUrl())
+	server := documenttest.NewServer(t, "testdata")
+	c := &CrawlerImpl{HttpFetcher: document.NewHttpFetcher(server.Client())}
+
+	urlFrontiers, err := c.fetchListingPage(context.Background(), server.URL+"/listing.html")
+	if err != nil {
+		t.Fatalf("fetchListingPage() error = %v", err)
+	}
+
+	results := make([]listingResult, len(urlFrontiers))
+	for i, urlFrontier := range urlFrontiers {
+		if urlFrontier.ID != models.UrlFrontierId(urlFrontier.Url) {
+			t.Errorf("url frontier %s has id %s", urlFrontier.Url, urlFrontier.ID)
+		}
+		results[i] = listingResult{Url: urlFrontier.Url, Metadata: urlFrontier.Metadata}
+	}
+	documenttest.AssertGolden(t, "testdata/listing.golden.json", results)
+}
+
+func TestLastPage(t *testing.T) {
+	server := documenttest.NewServer(t, "testdata")
+	c := &CrawlerImpl{HttpFetcher: document.NewHttpFetcher(server.Client())}
+
+	lastPage, err := c.lastPage(context.Background(), server.URL+"/listing.html")
+	if err != nil {
+		t.Fatalf("lastPage() error = %v", err)
+	}
+
+	documenttest.AssertGolden(t, "testdata/listing.last_page.golden.json", map[string]int{"pages": lastPage.A, "results": lastPage.B})
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RetryPolicy decides when a url frontier that failed to scrape is selected again.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts before the url frontier is dead-lettered
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   5 * time.Minute,
+		MaxDelay:    24 * time.Hour,
+	}
+}
+
+// Backoff is the delay after the given number of failed attempts, doubling from BaseDelay up to MaxDelay.
+func (p RetryPolicy) Backoff(attempts int32) time.Duration {
+	delay := p.BaseDelay
+	for i := int32(1); i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// IsExhausted reports whether a url frontier with this number of attempts is dead-lettered.
+func (p RetryPolicy) IsExhausted(attempts int32) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
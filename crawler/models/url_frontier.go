@@ -1,21 +1,59 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"lexicon/singapore-supreme-court-crawler/citation"
 )
 
 const (
 	URL_FRONTIER_STATUS_NEW     int16 = 0
 	URL_FRONTIER_STATUS_CRAWLED int16 = 1
 	URL_FRONTIER_STATUS_ERROR   int16 = 2
+	// Dead-lettered after too many failed attempts, never selected again
+	URL_FRONTIER_STATUS_DEAD int16 = 3
+	// The download was not the expected document, e.g. an error page saved as the PDF.
+	// Selected again like URL_FRONTIER_STATUS_ERROR, but counted apart
+	URL_FRONTIER_STATUS_INVALID_ARTIFACT int16 = 4
 )
 
+// ErrInvalidArtifact is wrapped by the errors of downloads rejected by the artifact validation.
+// A scrape failing with it gives its url frontier the URL_FRONTIER_STATUS_INVALID_ARTIFACT status.
+var ErrInvalidArtifact = errors.New("invalid artifact")
+
+func UrlFrontierStatusName(status int16) string {
+	switch status {
+	case URL_FRONTIER_STATUS_NEW:
+		return "new"
+	case URL_FRONTIER_STATUS_CRAWLED:
+		return "crawled"
+	case URL_FRONTIER_STATUS_ERROR:
+		return "error"
+	case URL_FRONTIER_STATUS_DEAD:
+		return "dead"
+	case URL_FRONTIER_STATUS_INVALID_ARTIFACT:
+		return "invalid_artifact"
+	default:
+		return "unknown"
+	}
+}
+
+// UrlFrontierId is the id of the url frontier of url, the hex sha256 of the url.
+func UrlFrontierId(url string) string {
+	id := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(id[:])
+}
+
 type UrlFrontierMetadata struct {
-	CitationNumber string   `json:"citation_number"`
-	DecisionDate   string   `json:"decision_date"`
-	Title          string   `json:"title"`
-	Categories     []string `json:"categories"`
-	CaseNumbers    []string `json:"case_numbers"`
+	CitationNumber string `json:"citation_number"`
+	// Citation is CitationNumber parsed, nil when it is not a valid neutral citation
+	Citation     *citation.Citation `json:"citation,omitempty"`
+	DecisionDate string             `json:"decision_date"`
+	Title        string             `json:"title"`
+	Categories   []string           `json:"categories"`
+	CaseNumbers  []string           `json:"case_numbers"`
 }
 
 func (m *UrlFrontierMetadata) ToJson() ([]byte, error) {
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// GetKnownUrlFrontierIds returns the ids that are already stored in the url frontier.
+func GetKnownUrlFrontierIds(ctx context.Context, ids []string) ([]string, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	knownIds, err := queries.GetUrlFrontierIds(ctx, ids)
+	if err != nil {
+		log.Err(err).Msg("failed to get known url frontier ids")
+		return nil, err
+	}
+
+	return knownIds, nil
+}
+
+// GetCrawlWatermark returns the latest decision date crawled for jobKey, nil when the job was never crawled.
+func GetCrawlWatermark(ctx context.Context, jobKey string) (*time.Time, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	watermark, err := queries.GetCrawlWatermark(ctx, repository.GetCrawlWatermarkParams{
+		Crawler: common.CRAWLER_NAME,
+		JobKey:  jobKey,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to get crawl watermark")
+		return nil, err
+	}
+
+	return &watermark.LastDecisionDate, nil
+}
+
+// SaveCrawlWatermark records a crawl of jobKey, the stored decision date never moves backwards.
+func SaveCrawlWatermark(ctx context.Context, jobKey string, lastDecisionDate time.Time) error {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	err = queries.UpsertCrawlWatermark(ctx, repository.UpsertCrawlWatermarkParams{
+		Crawler:          common.CRAWLER_NAME,
+		JobKey:           jobKey,
+		LastDecisionDate: lastDecisionDate,
+		LastCrawledAt:    time.Now(),
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to upsert crawl watermark")
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
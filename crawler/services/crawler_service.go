@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
 	"lexicon/singapore-supreme-court-crawler/common"
 	"lexicon/singapore-supreme-court-crawler/crawler/models"
 	"lexicon/singapore-supreme-court-crawler/repository"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 )
@@ -92,6 +94,8 @@ func GetUrlFrontierByUrl(ctx context.Context, url string) (repository.UrlFrontie
 		return repository.UrlFrontier{}, err
 	}
 
+	defer tx.Rollback(ctx)
+
 	queries := common.Query.WithTx(tx)
 
 	urlFrontier, err := queries.GetUrlFrontierByUrl(ctx, url)
@@ -111,6 +115,8 @@ func GetUrlFrontierById(ctx context.Context, id string) (repository.UrlFrontier,
 		return repository.UrlFrontier{}, err
 	}
 
+	defer tx.Rollback(ctx)
+
 	queries := common.Query.WithTx(tx)
 
 	urlFrontier, err := queries.GetUrlFrontierById(ctx, id)
@@ -122,24 +128,268 @@ func GetUrlFrontierById(ctx context.Context, id string) (repository.UrlFrontier,
 	return urlFrontier, nil
 }
 
-func GetUnscrappedUrlFrontiers(ctx context.Context, limit int32) ([]repository.UrlFrontier, error) {
+func GetUrlFrontiersByIds(ctx context.Context, ids []string) ([]repository.UrlFrontier, error) {
 	tx, err := common.Pool.Begin(ctx)
 	if err != nil {
 		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	urlFrontiers, err := queries.GetUrlFrontiersByIds(ctx, ids)
+	if err != nil {
+		log.Err(err).Msg("failed to get url frontiers by ids")
+		return nil, err
+	}
+
+	return urlFrontiers, nil
+}
+
+// LeaseUnscrappedUrlFrontiers claims up to limit url frontiers waiting to be scraped for leaseDuration.
+// Rows locked by another scrapper are skipped, so concurrent scrappers never get the same url frontier.
+func LeaseUnscrappedUrlFrontiers(ctx context.Context, limit int32, leaseDuration time.Duration) ([]repository.UrlFrontier, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	now := time.Now()
+	urlFrontiers, err := queries.LeaseUnscrappedUrlFrontiers(ctx, repository.LeaseUnscrappedUrlFrontiersParams{
+		LeasedUntil:           now.Add(leaseDuration),
+		Crawler:               common.CRAWLER_NAME,
+		NewStatus:             models.URL_FRONTIER_STATUS_NEW,
+		ErrorStatus:           models.URL_FRONTIER_STATUS_ERROR,
+		InvalidArtifactStatus: models.URL_FRONTIER_STATUS_INVALID_ARTIFACT,
+		Now:                   now,
+		MaxResults:            limit,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to lease unscrapped url frontiers")
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return urlFrontiers, nil
+}
+
+// LeaseUrlFrontier claims the url frontier id for leaseDuration when it is due to be scraped and not leased by another scrapper.
+// It returns the url frontier and whether it was leased, pgx.ErrNoRows when it does not exist.
+func LeaseUrlFrontier(ctx context.Context, id string, leaseDuration time.Duration) (repository.UrlFrontier, bool, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return repository.UrlFrontier{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	now := time.Now()
+	urlFrontier, err := queries.LeaseUrlFrontier(ctx, repository.LeaseUrlFrontierParams{
+		LeasedUntil:           now.Add(leaseDuration),
+		ID:                    id,
+		NewStatus:             models.URL_FRONTIER_STATUS_NEW,
+		ErrorStatus:           models.URL_FRONTIER_STATUS_ERROR,
+		InvalidArtifactStatus: models.URL_FRONTIER_STATUS_INVALID_ARTIFACT,
+		Now:                   now,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Already scraped, dead-lettered, not due yet or leased: the caller decides from the url frontier
+		urlFrontier, err := queries.GetUrlFrontierById(ctx, id)
+		return urlFrontier, false, err
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to lease url frontier")
+		return repository.UrlFrontier{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repository.UrlFrontier{}, false, err
+	}
+
+	return urlFrontier, true, nil
+}
+
+// UrlFrontierAvailableAt is when urlFrontier, not leased by LeaseUrlFrontier, may be leased again:
+// the end of the lease of another scrapper or the next attempt of a failed url frontier, whichever is later.
+func UrlFrontierAvailableAt(urlFrontier repository.UrlFrontier) time.Time {
+	var availableAt time.Time
+	if urlFrontier.NextAttemptAt != nil && urlFrontier.Status != models.URL_FRONTIER_STATUS_NEW {
+		availableAt = *urlFrontier.NextAttemptAt
+	}
+	if urlFrontier.LeasedUntil != nil && urlFrontier.LeasedUntil.After(availableAt) {
+		availableAt = *urlFrontier.LeasedUntil
+	}
+	return availableAt
+}
+
+// ListDueUrlFrontiers returns the next page of url frontiers after afterId, ordered by id, that LeaseUnscrappedUrlFrontiers
+// would lease now, without leasing them.
+func ListDueUrlFrontiers(ctx context.Context, afterId string, limit int32) ([]repository.UrlFrontier, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	urlFrontiers, err := queries.ListDueUrlFrontiers(ctx, repository.ListDueUrlFrontiersParams{
+		Crawler:               common.CRAWLER_NAME,
+		NewStatus:             models.URL_FRONTIER_STATUS_NEW,
+		ErrorStatus:           models.URL_FRONTIER_STATUS_ERROR,
+		InvalidArtifactStatus: models.URL_FRONTIER_STATUS_INVALID_ARTIFACT,
+		Now:                   time.Now(),
+		AfterID:               afterId,
+		MaxResults:            limit,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to list due url frontiers")
+		return nil, err
+	}
 
+	return urlFrontiers, nil
+}
+
+// ListScrappedUrlFrontiers returns the next page of scraped url frontiers after afterId, ordered by id.
+func ListScrappedUrlFrontiers(ctx context.Context, afterId string, limit int32) ([]repository.UrlFrontier, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
+
 	queries := common.Query.WithTx(tx)
 
-	urlFrontiers, err := queries.GetUnscrappedUrlFrontiers(ctx, repository.GetUnscrappedUrlFrontiersParams{
-		Crawler: common.CRAWLER_NAME,
-		Status:  models.URL_FRONTIER_STATUS_NEW,
-		Limit:   limit,
+	urlFrontiers, err := queries.ListScrappedUrlFrontiers(ctx, repository.ListScrappedUrlFrontiersParams{
+		Crawler:    common.CRAWLER_NAME,
+		Status:     models.URL_FRONTIER_STATUS_CRAWLED,
+		AfterID:    afterId,
+		MaxResults: limit,
 	})
 	if err != nil {
-		log.Err(err).Msg("failed to get unscrapped url frontiers")
+		log.Err(err).Msg("failed to list scrapped url frontiers")
 		return nil, err
 	}
 
 	return urlFrontiers, nil
 }
+
+func CountUrlFrontiersByStatus(ctx context.Context) ([]repository.CountUrlFrontiersByStatusRow, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	counts, err := queries.CountUrlFrontiersByStatus(ctx, common.CRAWLER_NAME)
+	if err != nil {
+		log.Err(err).Msg("failed to count url frontiers by status")
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetPendingUrlFrontiers returns the url frontiers among ids that are still waiting to be scraped.
+func GetPendingUrlFrontiers(ctx context.Context, ids []string) ([]repository.UrlFrontier, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	pending, err := queries.GetUrlFrontiersByStatus(ctx, repository.GetUrlFrontiersByStatusParams{
+		Ids:    ids,
+		Status: models.URL_FRONTIER_STATUS_NEW,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to get pending url frontiers")
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// NewUrlFrontierAttempt records one scrape attempt of urlFrontier that ended with err, nil on success.
+// A failed url frontier is scheduled again after the backoff of the policy, or dead-lettered once its attempts are exhausted.
+// Failures caused by an invalid artifact are scheduled the same way under their own status.
+func NewUrlFrontierAttempt(policy models.RetryPolicy, urlFrontier repository.UrlFrontier, err error, now time.Time) repository.UpdateUrlFrontierAttemptParams {
+	attempt := repository.UpdateUrlFrontierAttemptParams{
+		ID:        urlFrontier.ID,
+		Status:    models.URL_FRONTIER_STATUS_CRAWLED,
+		Attempts:  urlFrontier.Attempts + 1,
+		UpdatedAt: now,
+	}
+	if err == nil {
+		return attempt
+	}
+
+	lastError := err.Error()
+	if len(lastError) > maxLastErrorLength {
+		lastError = lastError[:maxLastErrorLength]
+	}
+	attempt.LastError = &lastError
+
+	if policy.IsExhausted(attempt.Attempts) {
+		attempt.Status = models.URL_FRONTIER_STATUS_DEAD
+		return attempt
+	}
+
+	nextAttemptAt := now.Add(policy.Backoff(attempt.Attempts))
+	attempt.Status = models.URL_FRONTIER_STATUS_ERROR
+	if errors.Is(err, models.ErrInvalidArtifact) {
+		attempt.Status = models.URL_FRONTIER_STATUS_INVALID_ARTIFACT
+	}
+	attempt.NextAttemptAt = &nextAttemptAt
+	return attempt
+}
+
+const maxLastErrorLength = 2000
+
+func UpdateUrlFrontierAttempts(ctx context.Context, attempts []repository.UpdateUrlFrontierAttemptParams) error {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		log.Err(err).Msg("failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	var updateErrors []error
+	res := queries.UpdateUrlFrontierAttempt(ctx, attempts)
+	res.Exec(func(i int, err error) {
+		if err != nil {
+			log.Err(err).Msgf("failed to update url frontier attempt %s", attempts[i].ID)
+			updateErrors = append(updateErrors, err)
+		}
+	})
+	if len(updateErrors) > 0 {
+		return errors.Join(updateErrors...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// EnsureStream creates or updates the work queue stream holding listing urls and url frontier ids.
+func EnsureStream(ctx context.Context) error {
+	_, err := common.JetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       common.NATS_STREAM,
+		Subjects:   []string{common.NATS_LISTING_SUBJECT, common.NATS_URL_FRONTIER_SUBJECT},
+		Retention:  jetstream.WorkQueuePolicy,
+		Storage:    jetstream.FileStorage,
+		Duplicates: common.NATS_DUPLICATE_WINDOW,
+	})
+	if err != nil {
+		log.Err(err).Msg("failed to create or update stream")
+		return err
+	}
+
+	return nil
+}
+
+// EnsureConsumer creates or updates the durable consumer name reading subject.
+// A message is delivered at most maxDeliver times.
+func EnsureConsumer(ctx context.Context, name string, subject string, maxDeliver int) (jetstream.Consumer, error) {
+	consumer, err := common.JetStream.CreateOrUpdateConsumer(ctx, common.NATS_STREAM, jetstream.ConsumerConfig{
+		Durable:       name,
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       common.NATS_ACK_WAIT,
+		MaxDeliver:    maxDeliver,
+	})
+	if err != nil {
+		log.Err(err).Msgf("failed to create or update consumer %s", name)
+		return nil, err
+	}
+
+	return consumer, nil
+}
+
+// PublishUrlFrontiers queues the ids of urlFrontiers for the scrapper.
+// The message id is the url frontier id with its number of attempts: a url frontier queued twice
+// for the same attempt is scraped once, while one due again after a failed attempt is queued anew.
+func PublishUrlFrontiers(ctx context.Context, urlFrontiers []repository.UrlFrontier) error {
+	return publish(ctx, common.NATS_URL_FRONTIER_SUBJECT, lo.Map(urlFrontiers, func(urlFrontier repository.UrlFrontier, _ int) queueMessage {
+		return queueMessage{Payload: urlFrontier.ID, ID: urlFrontierMsgID(urlFrontier)}
+	}))
+}
+
+// urlFrontierMsgID is the message id of urlFrontier in the work queue.
+func urlFrontierMsgID(urlFrontier repository.UrlFrontier) string {
+	return fmt.Sprintf("%s.%d", urlFrontier.ID, urlFrontier.Attempts)
+}
+
+// RequeueDueUrlFrontiers publishes every url frontier due to be scraped, batchSize at a time, and returns how many were published.
+// It picks up the failed url frontiers once their next attempt is due, whose messages were settled before that.
+func RequeueDueUrlFrontiers(ctx context.Context, batchSize int32) (int, error) {
+	published := 0
+	afterId := ""
+	for {
+		urlFrontiers, err := ListDueUrlFrontiers(ctx, afterId, batchSize)
+		if err != nil {
+			return published, err
+		}
+		if len(urlFrontiers) == 0 {
+			return published, nil
+		}
+
+		if err := PublishUrlFrontiers(ctx, urlFrontiers); err != nil {
+			return published, err
+		}
+		published += len(urlFrontiers)
+		afterId = urlFrontiers[len(urlFrontiers)-1].ID
+	}
+}
+
+// PublishListingUrls queues listing page urls for the crawler.
+// Every crawl queues its listing pages again, so they carry no message id and are never dropped as duplicates.
+func PublishListingUrls(ctx context.Context, urls []string) error {
+	return publish(ctx, common.NATS_LISTING_SUBJECT, lo.Map(urls, func(url string, _ int) queueMessage {
+		return queueMessage{Payload: url}
+	}))
+}
+
+// queueMessage is a message of the work queue, deduplicated by JetStream on ID when it is set.
+type queueMessage struct {
+	Payload string
+	ID      string
+}
+
+func publish(ctx context.Context, subject string, messages []queueMessage) error {
+	var publishErrors []error
+	for _, message := range messages {
+		var opts []jetstream.PublishOpt
+		if message.ID != "" {
+			opts = append(opts, jetstream.WithMsgID(message.ID))
+		}
+		_, err := common.JetStream.Publish(ctx, subject, []byte(message.Payload), opts...)
+		if err != nil {
+			log.Err(err).Msgf("failed to publish %s to %s", message.Payload, subject)
+			publishErrors = append(publishErrors, err)
+		}
+	}
+
+	if len(publishErrors) > 0 {
+		return fmt.Errorf("failed to publish %d of %d messages to %s: %w", len(publishErrors), len(messages), subject, errors.Join(publishErrors...))
+	}
+
+	return nil
+}
+
+// ConsumeMessages hands the messages of consumer to handle from workers goroutines until ctx is done.
+// handle is responsible for acknowledging every message.
+func ConsumeMessages(ctx context.Context, consumer jetstream.Consumer, workers int, handle func(m jetstream.Msg) error) error {
+	messages, err := consumer.Messages(jetstream.PullMaxMessages(workers))
+	if err != nil {
+		log.Err(err).Msg("failed to start consuming messages")
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		messages.Drain()
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				m, err := messages.Next()
+				if err != nil {
+					if errors.Is(err, jetstream.ErrMsgIteratorClosed) || errors.Is(err, nats.ErrConnectionClosed) {
+						return
+					}
+					log.Err(err).Msg("failed to get next message")
+					continue
+				}
+				if err := handle(m); err != nil {
+					log.Err(err).Msgf("failed to handle message on %s", m.Subject())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// RedeliveryDelay is the exponential delay before a failed message is delivered again.
+func RedeliveryDelay(m jetstream.Msg) time.Duration {
+	delay := 30 * time.Second
+	metadata, err := m.Metadata()
+	if err != nil {
+		return delay
+	}
+
+	for i := uint64(1); i < metadata.NumDelivered && delay < 10*time.Minute; i++ {
+		delay *= 2
+	}
+	return min(delay, 10*time.Minute)
+}
+
+// IsLastDelivery reports whether m will not be delivered again after a nak.
+func IsLastDelivery(m jetstream.Msg, maxDeliver int) bool {
+	metadata, err := m.Metadata()
+	if err != nil {
+		return false
+	}
+	return maxDeliver > 0 && metadata.NumDelivered >= uint64(maxDeliver)
+}
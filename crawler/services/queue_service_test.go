@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/common/natstest"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func startQueue(t *testing.T) jetstream.JetStream {
+	t.Helper()
+	_, js := natstest.Start(t)
+	if err := EnsureStream(context.Background()); err != nil {
+		t.Fatalf("EnsureStream() error = %v", err)
+	}
+	return js
+}
+
+func queuedMessages(t *testing.T, js jetstream.JetStream) uint64 {
+	t.Helper()
+	stream, err := js.Stream(context.Background(), common.NATS_STREAM)
+	if err != nil {
+		t.Fatalf("failed to get stream: %v", err)
+	}
+	return stream.CachedInfo().State.Msgs
+}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		publish func(ctx context.Context) error
+		want    uint64
+	}{
+		{
+			name: "url frontier queued twice before its attempt",
+			publish: func(ctx context.Context) error {
+				urlFrontiers := []repository.UrlFrontier{{ID: "a"}, {ID: "b"}}
+				if err := PublishUrlFrontiers(ctx, urlFrontiers); err != nil {
+					return err
+				}
+				return PublishUrlFrontiers(ctx, urlFrontiers)
+			},
+			want: 2,
+		},
+		{
+			name: "url frontier queued again after an attempt",
+			publish: func(ctx context.Context) error {
+				if err := PublishUrlFrontiers(ctx, []repository.UrlFrontier{{ID: "a"}}); err != nil {
+					return err
+				}
+				return PublishUrlFrontiers(ctx, []repository.UrlFrontier{{ID: "a", Attempts: 1}})
+			},
+			want: 2,
+		},
+		{
+			name: "listing queued by two crawls",
+			publish: func(ctx context.Context) error {
+				urls := []string{"https://www.elitigation.sg/gd/Home/Index?currentPage=1"}
+				if err := PublishListingUrls(ctx, urls); err != nil {
+					return err
+				}
+				return PublishListingUrls(ctx, urls)
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			js := startQueue(t)
+			if err := tt.publish(context.Background()); err != nil {
+				t.Fatalf("publish error = %v", err)
+			}
+			if got := queuedMessages(t, js); got != tt.want {
+				t.Errorf("queued messages = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeMessages(t *testing.T) {
+	js := startQueue(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consumer, err := EnsureConsumer(ctx, "scrapper", common.NATS_URL_FRONTIER_SUBJECT, 3)
+	if err != nil {
+		t.Fatalf("EnsureConsumer() error = %v", err)
+	}
+	urlFrontiers := []repository.UrlFrontier{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	if err := PublishUrlFrontiers(ctx, urlFrontiers); err != nil {
+		t.Fatalf("PublishUrlFrontiers() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var handled []string
+	err = ConsumeMessages(ctx, consumer, 2, func(m jetstream.Msg) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, string(m.Data()))
+		if len(handled) == len(urlFrontiers) {
+			cancel()
+		}
+		return m.Ack()
+	})
+	if err != context.Canceled {
+		t.Errorf("ConsumeMessages() error = %v, want context.Canceled", err)
+	}
+
+	slices.Sort(handled)
+	if !slices.Equal(handled, []string{"a", "b", "c"}) {
+		t.Errorf("handled = %v, want every url frontier once", handled)
+	}
+	if got := queuedMessages(t, js); got != 0 {
+		t.Errorf("queued messages = %d, want the acked messages removed", got)
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	startQueue(t)
+	ctx := context.Background()
+
+	consumer, err := EnsureConsumer(ctx, "scrapper", common.NATS_URL_FRONTIER_SUBJECT, 2)
+	if err != nil {
+		t.Fatalf("EnsureConsumer() error = %v", err)
+	}
+	if err := PublishUrlFrontiers(ctx, []repository.UrlFrontier{{ID: "a"}}); err != nil {
+		t.Fatalf("PublishUrlFrontiers() error = %v", err)
+	}
+
+	m := natstest.Next(t, consumer)
+	if IsLastDelivery(m, 2) || RedeliveryDelay(m) != 30*time.Second {
+		t.Errorf("first delivery: IsLastDelivery() = %v, RedeliveryDelay() = %v", IsLastDelivery(m, 2), RedeliveryDelay(m))
+	}
+	if err := m.Nak(); err != nil {
+		t.Fatal(err)
+	}
+
+	m = natstest.Next(t, consumer)
+	if !IsLastDelivery(m, 2) || RedeliveryDelay(m) != time.Minute {
+		t.Errorf("second delivery: IsLastDelivery() = %v, RedeliveryDelay() = %v", IsLastDelivery(m, 2), RedeliveryDelay(m))
+	}
+}
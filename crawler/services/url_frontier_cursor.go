@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"time"
+)
+
+// UrlFrontierCursor streams the url frontiers waiting to be scraped, leasing them one batch at a time.
+// A leased url frontier is hidden from other cursors until its attempt is recorded or its lease expires.
+type UrlFrontierCursor struct {
+	batchSize     int32
+	leaseDuration time.Duration
+	leased        int
+}
+
+func NewUrlFrontierCursor(batchSize int32, leaseDuration time.Duration) *UrlFrontierCursor {
+	return &UrlFrontierCursor{
+		batchSize:     batchSize,
+		leaseDuration: leaseDuration,
+	}
+}
+
+// Next leases the next batch of url frontiers. An empty batch means the queue is drained.
+func (c *UrlFrontierCursor) Next(ctx context.Context) ([]repository.UrlFrontier, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	urlFrontiers, err := LeaseUnscrappedUrlFrontiers(ctx, c.batchSize, c.leaseDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	c.leased += len(urlFrontiers)
+	return urlFrontiers, nil
+}
+
+// Leased is the number of url frontiers leased so far.
+func (c *UrlFrontierCursor) Leased() int {
+	return c.leased
+}
@@ -2,14 +2,14 @@ package crawler
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"lexicon/singapore-supreme-court-crawler/common"
 	"lexicon/singapore-supreme-court-crawler/crawler/models"
 	"lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/document"
 	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/retry"
 	"sync"
 	"time"
 
@@ -18,6 +18,7 @@ import (
 	"strconv"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
@@ -35,7 +36,7 @@ type urlCrawler struct {
 }
 
 func (u *urlCrawler) constructUrl() string {
-	return fmt.Sprintf("%s?filter=%s&yearOfDecision=%s&sortBy=%s&currentPage=%d&sortAscending=%t&searchPhrase=%s&verbose=%t", u.BaseUrl, u.Filter, u.YearOfDecision, u.SortBy, u.CurrentPage, u.SortAscending, u.SearchPhrase, u.Verbose)
+	return fmt.Sprintf("%s?filter=%s&yearOfDecision=%s&sortBy=%s&currentPage=%d&sortAscending=%t&searchPhrase=%s&verbose=%t", u.BaseUrl, stdUrl.QueryEscape(u.Filter), stdUrl.QueryEscape(u.YearOfDecision), stdUrl.QueryEscape(u.SortBy), u.CurrentPage, u.SortAscending, stdUrl.QueryEscape(u.SearchPhrase), u.Verbose)
 }
 
 func (u *urlCrawler) copy() urlCrawler {
@@ -109,98 +110,188 @@ type Crawler interface {
 
 type CrawlerImpl struct {
 	browser *rod.Browser
+	pages   rod.Pool[rod.Page]
+	// HttpFetcher downloads the listing pages over plain HTTP instead of the browser when set
+	HttpFetcher *document.HttpFetcher
+	// Jobs are the searches crawled by CrawlAll, DefaultCrawlJob when empty
+	Jobs []CrawlJob
+	// MaxDeliver is the number of deliveries of a message before Consume gives up on it
+	MaxDeliver int
+	// Incremental stops crawling a job once its listing pages only hold known judgements
+	Incremental bool
+	// UserAgent identifies the browser pages, the browser's own when empty
+	UserAgent string
+	// Concurrency is the number of listing pages crawled at once, DEFAULT_CRAWL_CONCURRENCY when zero
+	Concurrency int
+	// FetchRetry retries the transient failures of a listing page, DefaultPolicy when zero
+	FetchRetry retry.Policy
+	// Stats counts the retries and failed listing pages of the run, nothing when nil
+	Stats *retry.Stats
 }
 
 func (c *CrawlerImpl) Setup() {
+	if c.HttpFetcher != nil {
+		return
+	}
 	c.browser = rod.New().MustConnect()
+	c.pages = rod.NewPagePool(c.concurrency())
 }
 
 func (c *CrawlerImpl) Teardown() {
-	c.browser.MustClose()
-}
-
-func (c *CrawlerImpl) CrawlAll(ctx context.Context) error {
-	startUrl := "https://www.elitigation.sg/gd/Home/Index?filter=SUPCT&yearOfDecision=All&sortBy=DateOfDecision&currentPage=1&sortAscending=False&searchPhrase=CatchWords:Corruption&verbose=False"
-
-	pagePool := rod.NewPagePool(7)
-	defer pagePool.Cleanup(func(p *rod.Page) {
+	if c.browser == nil {
+		return
+	}
+	c.pages.Cleanup(func(p *rod.Page) {
 		err := p.Close()
 		if err != nil {
 			log.Error().Err(err).Msg("Error closing page")
 		}
 	})
+	c.browser.MustClose()
+}
 
-	create := func() (*rod.Page, error) {
+func (c *CrawlerImpl) CrawlAll(ctx context.Context) error {
+	jobs := c.Jobs
+	if len(jobs) == 0 {
+		jobs = []CrawlJob{DefaultCrawlJob()}
+	}
 
-		incognito, err := c.browser.Incognito()
-		if err != nil {
-			log.Error().Err(err).Msg("Error creating incognito page")
-			return nil, err
+	var jobErrors []error
+	for _, job := range jobs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
 		}
-		return incognito.MustPage(), nil
 
-	}
-
-	createListOfUrl := func(urlCrawler urlCrawler, startPage int, endPage int) []string {
-		urls := []string{}
-		for i := startPage; i <= endPage; i++ {
-			newUrlCrawler := urlCrawler.copy()
-			newUrlCrawler.CurrentPage = i
-			urls = append(urls, newUrlCrawler.constructUrl())
+		log.Info().Msgf("Crawling job %s: %s", job.Name, job.SearchPhrase)
+		if err := c.crawlJob(ctx, job); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			log.Error().Err(err).Msgf("Error crawling job %s", job.Name)
+			jobErrors = append(jobErrors, fmt.Errorf("job %s: %w", job.Name, err))
 		}
-		return urls
 	}
 
-	job := func(urlPage string) error {
+	log.Info().Msgf("Crawl summary: %s", c.Stats.Summary())
+	return errors.Join(jobErrors...)
+}
+
+// EnqueueAll publishes the listing pages of every job for the crawler consumers instead of crawling them.
+func (c *CrawlerImpl) EnqueueAll(ctx context.Context) error {
+	jobs := c.Jobs
+	if len(jobs) == 0 {
+		jobs = []CrawlJob{DefaultCrawlJob()}
+	}
 
-		page, err := pagePool.Get(create)
+	var jobErrors []error
+	for _, job := range jobs {
+		urlList, err := c.listingUrls(ctx, job)
 		if err != nil {
-			log.Error().Err(err).Msg("Error getting page")
-			return err
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			jobErrors = append(jobErrors, fmt.Errorf("job %s: %w", job.Name, err))
+			continue
 		}
-		defer pagePool.Put(page)
-		return c.crawlJudgement(ctx, page, urlPage)
 
+		log.Info().Msgf("Enqueueing %d listing pages of job %s", len(urlList), job.Name)
+		if err := services.PublishListingUrls(ctx, urlList); err != nil {
+			jobErrors = append(jobErrors, fmt.Errorf("job %s: %w", job.Name, err))
+		}
 	}
-	parsedUrl, err := stdUrl.Parse(startUrl)
+
+	return errors.Join(jobErrors...)
+}
+
+func (c *CrawlerImpl) newPage() (*rod.Page, error) {
+	incognito, err := c.browser.Incognito()
 	if err != nil {
-		log.Error().Err(err).Msg("Error parsing URL")
-		return err
-	}
-	currentPage := parsedUrl.Query().Get("currentPage")
-	if currentPage == "" {
-		currentPage = "1"
+		log.Error().Err(err).Msg("Error creating incognito page")
+		return nil, err
 	}
-	currentPageInt, err := strconv.Atoi(currentPage)
+	page, err := incognito.Page(proto.TargetCreateTarget{})
 	if err != nil {
-		log.Error().Err(err).Msg("Error converting currentPage to integer")
-		return err
+		log.Error().Err(err).Msg("Error creating page")
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: c.UserAgent}); err != nil {
+			log.Error().Err(err).Msg("Error setting user agent")
+			return nil, err
+		}
 	}
+	return page, nil
+}
 
-	rpLast, err := create()
+func (c *CrawlerImpl) fetchRetry() retry.Policy {
+	if c.FetchRetry == (retry.Policy{}) {
+		return retry.DefaultPolicy()
+	}
+	return c.FetchRetry
+}
+
+func (c *CrawlerImpl) concurrency() int {
+	if c.Concurrency <= 0 {
+		return common.DEFAULT_CRAWL_CONCURRENCY
+	}
+	return c.Concurrency
+}
+
+// listingUrls returns the url of every listing page of the job, from its start page to its last page.
+func (c *CrawlerImpl) listingUrls(ctx context.Context, job CrawlJob) ([]string, error) {
+	startUrl := job.StartUrl()
+
+	urlCrawler, err := newUrlCrawler(startUrl)
 	if err != nil {
-		return fmt.Errorf("failed to create page for last page check: %w", err)
+		log.Error().Err(err).Msg("Error creating url crawler")
+		return nil, err
 	}
-	defer rpLast.Close()
 
-	lastPage, err := getLastPage(ctx, rpLast, startUrl)
+	lastPage, err := c.lastPage(ctx, startUrl)
 	if err != nil {
-		return fmt.Errorf("failed to get last page: %w", err)
+		return nil, fmt.Errorf("failed to get last page: %w", err)
 	}
 
 	lastPageInt, totalResult := lastPage.Unpack()
 
 	log.Info().Msg("Total result: " + strconv.Itoa(totalResult))
 
-	urlCrawler, err := newUrlCrawler(startUrl)
+	urls := []string{}
+	for i := urlCrawler.CurrentPage; i <= lastPageInt; i++ {
+		newUrlCrawler := urlCrawler.copy()
+		newUrlCrawler.CurrentPage = i
+		urls = append(urls, newUrlCrawler.constructUrl())
+	}
+	return urls, nil
+}
+
+func (c *CrawlerImpl) crawlJob(ctx context.Context, job CrawlJob) error {
+	if c.Incremental {
+		if job.isNewestFirst() {
+			return c.crawlJobIncremental(ctx, job)
+		}
+		log.Warn().Msgf("Job %s is not sorted by decision date descending, crawling it fully", job.Name)
+	}
+
+	watermark := newWatermarkTracker()
+	crawlPage := func(urlPage string) error {
+		urlFrontiers, err := c.crawlJudgement(ctx, urlPage)
+		if err != nil {
+			return err
+		}
+		watermark.observe(urlFrontiers)
+		return nil
+
+	}
+
+	urlList, err := c.listingUrls(ctx, job)
 	if err != nil {
-		log.Error().Err(err).Msg("Error creating url crawler")
 		return err
 	}
 
-	urlList := createListOfUrl(urlCrawler, currentPageInt, lastPageInt)
-
-	chunks := lo.Chunk(urlList, 7)
+	chunks := lo.Chunk(urlList, c.concurrency())
 
 	var crawlErrors []error
 	errChan := make(chan error, len(urlList)) // Channel to collect errors
@@ -231,7 +322,7 @@ func (c *CrawlerImpl) CrawlAll(ctx context.Context) error {
 					errChan <- ctx.Err()
 					return
 				default:
-					if err := job(url); err != nil {
+					if err := crawlPage(url); err != nil {
 						errChan <- fmt.Errorf("error crawling %s: %w", url, err)
 						// Optional: cancel other goroutines if you want to stop on first error
 						// cancel()
@@ -240,11 +331,10 @@ func (c *CrawlerImpl) CrawlAll(ctx context.Context) error {
 			}(url)
 		}
 
-		go func() {
-			wg.Wait()
-			close(errChan)
-		}()
+		// Finish the chunk before starting the next one
+		wg.Wait()
 	}
+	close(errChan)
 
 	// Collect any errors that occurred
 	for err := range errChan {
@@ -261,81 +351,182 @@ func (c *CrawlerImpl) CrawlAll(ctx context.Context) error {
 		return fmt.Errorf("encountered %d errors during crawling: %v", len(crawlErrors), crawlErrors)
 	}
 
-	return nil
+	// Only a complete crawl moves the watermark, a gap would be skipped by the next incremental crawl
+	return watermark.save(ctx, job)
 }
 
+// Crawl crawls a single listing page into the url frontier.
 func (c *CrawlerImpl) Crawl(ctx context.Context, url string) error {
-	page := c.browser.MustPage(url)
+	if _, err := newUrlCrawler(url); err != nil {
+		return fmt.Errorf("not a listing url %s: %w", url, err)
+	}
 
-	c.crawlJudgement(ctx, page, url)
+	_, err := c.crawlJudgement(ctx, url)
+	return err
+}
 
-	return nil
+// Consume crawls the listing page url carried by a work queue message.
+func (c *CrawlerImpl) Consume(m jetstream.Msg) error {
+	return c.consume(m, c.Crawl)
+}
+
+// consume acks m once crawl succeeds, naks it for a later delivery when crawl fails
+// and terminates it when its url is not a listing page or on its last delivery.
+func (c *CrawlerImpl) consume(m jetstream.Msg, crawl func(ctx context.Context, url string) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), common.NATS_ACK_WAIT)
+	defer cancel()
+
+	url := string(m.Data())
+	if _, err := newUrlCrawler(url); err != nil {
+		if termErr := m.TermWithReason("not a listing url"); termErr != nil {
+			log.Error().Err(termErr).Msg("Error terminating message")
+		}
+		return fmt.Errorf("not a listing url %q: %w", url, err)
+	}
+
+	if err := crawl(ctx, url); err != nil {
+		maxDeliver := c.MaxDeliver
+		if maxDeliver == 0 {
+			maxDeliver = common.NATS_DEFAULT_MAX_DELIVER
+		}
+		if services.IsLastDelivery(m, maxDeliver) {
+			log.Error().Err(err).Msgf("Giving up on listing page %s", url)
+			if termErr := m.TermWithReason(err.Error()); termErr != nil {
+				log.Error().Err(termErr).Msg("Error terminating message")
+			}
+			return err
+		}
+		if nakErr := m.NakWithDelay(services.RedeliveryDelay(m)); nakErr != nil {
+			log.Error().Err(nakErr).Msg("Error naking message")
+		}
+		return err
+	}
 
+	return m.Ack()
 }
 
-func (c *CrawlerImpl) Consume(m jetstream.Msg) error {
+// crawlJudgement crawls a listing page into the url frontier and returns the frontiers found on it.
+func (c *CrawlerImpl) crawlJudgement(ctx context.Context, url string) ([]repository.UrlFrontier, error) {
+	urlFrontiers, err := c.fetchListingPage(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	if err := storeUrlFrontiers(ctx, urlFrontiers); err != nil {
+		return nil, err
+	}
+
+	log.Info().Msgf("Crawling url: %s done!", url)
+	return urlFrontiers, nil
 }
 
-func (c *CrawlerImpl) crawlJudgement(ctx context.Context, rp *rod.Page, url string) error {
+// fetchListingPage downloads a listing page and reads the url frontier of every judgement on it.
+func (c *CrawlerImpl) fetchListingPage(ctx context.Context, url string) ([]repository.UrlFrontier, error) {
 	log.Info().Msg("Crawling URL: " + url)
 
+	var detailUrls []repository.UrlFrontier
+	err := c.withListingPage(ctx, url, func(doc document.Node) error {
+		var err error
+		detailUrls, err = parseListingPage(doc)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newUrlFrontiers(detailUrls), nil
+}
+
+// lastPage reads the last page number and the total number of results of a listing.
+func (c *CrawlerImpl) lastPage(ctx context.Context, url string) (lo.Tuple2[int, int], error) {
+	var lastPage lo.Tuple2[int, int]
+	err := c.withListingPage(ctx, url, func(doc document.Node) error {
+		var err error
+		lastPage, err = parseLastPage(doc)
+		return err
+	})
+	return lastPage, err
+}
+
+// withListingPage downloads a listing page, over HTTP or in the browser, and calls read with its document.
+// Transient failures are retried. The browser page is returned to the pool once read is done with it.
+func (c *CrawlerImpl) withListingPage(ctx context.Context, url string, read func(doc document.Node) error) error {
+	err := retry.Do(ctx, c.fetchRetry(), c.Stats, url, func(ctx context.Context) error {
+		return c.loadListingPage(ctx, url, read)
+	})
+	c.Stats.Failed(err)
+	return err
+}
+
+func (c *CrawlerImpl) loadListingPage(ctx context.Context, url string, read func(doc document.Node) error) error {
+	if c.HttpFetcher != nil {
+		doc, err := c.HttpFetcher.Fetch(ctx, url)
+		if err != nil {
+			log.Error().Err(err).Msg("Error fetching url")
+			return err
+		}
+		return read(document.FromSelection(doc.Selection))
+	}
+
+	page, err := c.pages.Get(c.newPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting page")
+		return err
+	}
+	defer c.pages.Put(page)
+
+	doc, err := browseListingPage(ctx, page, url)
+	if err != nil {
+		return err
+	}
+	return read(doc)
+}
+
+// browseListingPage navigates to a listing page in the browser and waits for it to settle.
+func browseListingPage(ctx context.Context, rp *rod.Page, url string) (document.Node, error) {
 	// Check context before starting
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
+	if err := common.WaitForRequest(ctx, url); err != nil {
+		return nil, err
+	}
+
 	rpCtx := rp.Context(ctx)
-	wait := rpCtx.MustWaitNavigation()
+	wait := rpCtx.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
 	err := rpCtx.Navigate(url)
 	if err != nil {
 		log.Error().Err(err).Msg("Error navigating to url")
-		return err
+		return nil, retry.Transient(err)
 	}
 	wait()
 
 	// Check context after navigation
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
-	rp = rpCtx.MustWaitStable()
-
-	elements, err := rp.Elements("#listview > div.row > div.card.col-12")
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting elements")
-		return err
+	if err := rpCtx.WaitStable(time.Second); err != nil {
+		log.Error().Err(err).Msg("Error waiting for page to be stable")
+		return nil, retry.Transient(err)
 	}
 
-	log.Info().Msgf("Found %d elements", len(elements))
-
-	detailUrls := []repository.UrlFrontier{}
-	for _, element := range elements {
-
-		crawlerResult, err := getElementContent(element)
-
-		if err != nil {
-			log.Error().Err(err).Msg("Error getting element content")
-			continue
-		}
-
-		detailUrls = append(detailUrls, crawlerResult)
-		log.Info().Msg("Crawler result: " + crawlerResult.Url)
-	}
+	return document.FromPage(rpCtx)
+}
 
+// newUrlFrontiers turns the judgement cards read from a listing page into new url frontiers.
+func newUrlFrontiers(detailUrls []repository.UrlFrontier) []repository.UrlFrontier {
 	allDetails := []repository.UrlFrontier{}
 
 	for _, detail := range detailUrls {
 
-		id := sha256.Sum256([]byte(detail.Url))
 		currentTime := time.Now()
 		allDetails = append(allDetails, repository.UrlFrontier{
-			ID:        hex.EncodeToString(id[:]),
+			ID:        models.UrlFrontierId(detail.Url),
 			Url:       detail.Url,
 			Domain:    common.CRAWLER_DOMAIN,
 			Crawler:   common.CRAWLER_NAME,
@@ -346,126 +537,45 @@ func (c *CrawlerImpl) crawlJudgement(ctx context.Context, rp *rod.Page, url stri
 		})
 
 	}
-	log.Info().Msgf("Upserting %d urls", len(allDetails))
-	err = services.UpsertUrl(ctx, allDetails)
 
-	if err != nil {
-		log.Error().Err(err).Msg("Error upserting url")
-	}
-
-	log.Info().Msgf("Crawling url: %s done!", url)
-	return nil
+	return allDetails
 }
-func getElementContent(element *rod.Element) (repository.UrlFrontier, error) {
-	link := element.MustElement("a.h5.gd-heardertext").MustAttribute("href")
-	if link == nil {
-		return repository.UrlFrontier{}, errors.New("link is empty")
-	}
-	if !isDetailPage(*link) {
-		return repository.UrlFrontier{}, errors.New("link is not a detail page")
-	}
 
-	title, err := getTitle(element)
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting title")
-		return repository.UrlFrontier{}, err
-	}
-	caseNumbers, err := getCaseNumbers(element)
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting case numbers")
-		return repository.UrlFrontier{}, err
-	}
-	citationNumber, err := getCitationNumber(element)
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting citation number")
-		return repository.UrlFrontier{}, err
-	}
-	categories, err := getCategories(element)
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting categories")
-		return repository.UrlFrontier{}, err
-	}
+// storeUrlFrontiers upserts url frontiers and, with a work queue, publishes those still waiting to be scraped.
+func storeUrlFrontiers(ctx context.Context, urlFrontiers []repository.UrlFrontier) error {
+	log.Info().Msgf("Upserting %d urls", len(urlFrontiers))
+	err := services.UpsertUrl(ctx, urlFrontiers)
 
-	decisionDate, err := getDecisionDate(element)
 	if err != nil {
-		log.Error().Err(err).Msg("Error getting decision date")
-		return repository.UrlFrontier{}, err
+		log.Error().Err(err).Msg("Error upserting url")
+		return err
 	}
-	detailUrls := repository.UrlFrontier{
-		Url: fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, *link),
-		Metadata: models.UrlFrontierMetadata{
-			Title:          title,
-			CaseNumbers:    caseNumbers,
-			CitationNumber: citationNumber,
-			DecisionDate:   decisionDate,
-			Categories:     categories,
-		},
+
+	if common.JetStream != nil {
+		if err := publishPendingUrlFrontiers(ctx, urlFrontiers); err != nil {
+			log.Error().Err(err).Msg("Error publishing url frontiers")
+			return err
+		}
 	}
 
-	return detailUrls, nil
+	return nil
 }
 
-func getLastPage(ctx context.Context, rp *rod.Page, url string) (lo.Tuple2[int, int], error) {
-	lastPage := 0
-
-	if err := rp.Context(ctx).Navigate(url); err != nil {
-		if err == context.Canceled {
-			return lo.Tuple2[int, int]{}, err
-		}
-		log.Error().Err(err).Msg("Error navigating to url")
-		return lo.Tuple2[int, int]{}, err
-	}
-	totalResult := 0
-	totalResultElement, err := rp.Element("#listview > div.row.justify-content-between.align-items-center > div.gd-csummary")
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting total result element")
-		return lo.Tuple2[int, int]{}, err
-	}
-	res, err := totalResultElement.HTML()
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting total result element")
-		return lo.Tuple2[int, int]{}, err
-	}
-	totalResult, err = strconv.Atoi(regexp.MustCompile(`\d+`).FindString(res))
-	if err != nil {
-		log.Error().Err(err).Msg("Error converting total result to integer")
-		return lo.Tuple2[int, int]{}, err
+// publishPendingUrlFrontiers queues the frontiers that still wait to be scraped for the scrapper consumers.
+func publishPendingUrlFrontiers(ctx context.Context, urlFrontiers []repository.UrlFrontier) error {
+	if len(urlFrontiers) == 0 {
+		return nil
 	}
 
-	elements, err := rp.Elements("#listview > div.row.justify-content-end > div > ul > li.page-item.page-link> a")
+	pending, err := services.GetPendingUrlFrontiers(ctx, lo.Map(urlFrontiers, func(urlFrontier repository.UrlFrontier, _ int) string {
+		return urlFrontier.ID
+	}))
 	if err != nil {
-		log.Error().Err(err).Msg("Error getting elements")
-		return lo.Tuple2[int, int]{}, err
-	}
-	for _, element := range elements {
-		if element.MustHTML() == "" {
-			continue
-		}
-		href := element.MustAttribute("href")
-		if href == nil {
-			continue
-		}
-		u, err := stdUrl.Parse(*href)
-		if err != nil {
-			log.Error().Err(err).Msg("Error parsing URL")
-			continue
-		}
-		lp := u.Query().Get("CurrentPage")
-		lpInt, err := strconv.Atoi(lp)
-		if err != nil {
-			log.Error().Err(err).Msg("Error converting LP to integer")
-			continue
-		}
-		if lastPage < lpInt {
-			lastPage = lpInt
-		}
+		return err
 	}
 
-	return lo.Tuple2[int, int]{
-		A: lastPage,
-		B: totalResult,
-	}, nil
-
+	log.Info().Msgf("Publishing %d pending url frontiers", len(pending))
+	return services.PublishUrlFrontiers(ctx, pending)
 }
 
 func isDetailPage(link string) bool {
@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/common/natstest"
+	"lexicon/singapore-supreme-court-crawler/crawler/services"
+	"testing"
+)
+
+const listingUrl = "https://www.elitigation.sg/gd/Home/Index?filter=SUPCT&yearOfDecision=All&sortBy=DateOfDecision&currentPage=1&sortAscending=false&searchPhrase=&verbose=false"
+
+func TestConsume(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		// earlierDeliveries are naked before the message is consumed
+		earlierDeliveries int
+		crawlErr          error
+		want              string
+		wantCrawled       bool
+	}{
+		{name: "crawled", url: listingUrl, want: natstest.ACKED, wantCrawled: true},
+		{name: "not a listing url", url: "https://www.elitigation.sg/gd/s/2024_SGHC_1", want: natstest.TERMINATED},
+		{name: "failed", url: listingUrl, crawlErr: errors.New("timeout"), want: natstest.NAKED, wantCrawled: true},
+		{name: "failed on the last delivery", url: listingUrl, earlierDeliveries: 1, crawlErr: errors.New("timeout"), want: natstest.TERMINATED, wantCrawled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nc, _ := natstest.Start(t)
+			ctx := context.Background()
+			if err := services.EnsureStream(ctx); err != nil {
+				t.Fatal(err)
+			}
+			consumer, err := services.EnsureConsumer(ctx, common.NATS_CRAWLER_CONSUMER, common.NATS_LISTING_SUBJECT, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			watcher := natstest.Watch(t, nc, consumer)
+			if err := services.PublishListingUrls(ctx, []string{tt.url}); err != nil {
+				t.Fatal(err)
+			}
+			for range tt.earlierDeliveries {
+				if err := natstest.Next(t, consumer).Nak(); err != nil {
+					t.Fatal(err)
+				}
+				watcher.Outcome(t)
+			}
+
+			crawled := false
+			c := &CrawlerImpl{MaxDeliver: 2}
+			err = c.consume(natstest.Next(t, consumer), func(ctx context.Context, url string) error {
+				crawled = url == tt.url
+				return tt.crawlErr
+			})
+			if (err != nil) != (tt.want != natstest.ACKED) {
+				t.Errorf("consume() error = %v", err)
+			}
+			if got := watcher.Outcome(t); got != tt.want {
+				t.Errorf("message outcome = %s, want %s", got, tt.want)
+			}
+			if crawled != tt.wantCrawled {
+				t.Errorf("crawled = %v, want %v", crawled, tt.wantCrawled)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS url_frontiers_crawler_status_next_attempt_at_idx;
+
+ALTER TABLE url_frontiers
+  DROP COLUMN next_attempt_at,
+  DROP COLUMN last_error,
+  DROP COLUMN attempts;
@@ -0,0 +1,7 @@
+ALTER TABLE url_frontiers
+  ADD COLUMN attempts integer NOT NULL DEFAULT 0,
+  ADD COLUMN last_error text,
+  ADD COLUMN next_attempt_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS url_frontiers_crawler_status_next_attempt_at_idx
+  ON url_frontiers (crawler, status, next_attempt_at);
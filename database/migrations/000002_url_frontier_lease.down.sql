@@ -0,0 +1,2 @@
+ALTER TABLE url_frontiers
+  DROP COLUMN leased_until;
@@ -0,0 +1,2 @@
+ALTER TABLE url_frontiers
+  ADD COLUMN leased_until timestamptz;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS crawl_watermarks (
+  crawler text NOT NULL,
+  job_key text NOT NULL,
+  last_decision_date timestamptz NOT NULL,
+  last_crawled_at timestamptz NOT NULL,
+  PRIMARY KEY (crawler, job_key)
+);
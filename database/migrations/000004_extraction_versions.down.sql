@@ -0,0 +1,8 @@
+-- The HTML hashes cannot be restored, the next scrape stores text hashes again.
+COMMENT ON COLUMN extractions.page_hash IS NULL;
+
+DROP TABLE IF EXISTS extraction_versions;
+
+ALTER TABLE extractions
+  DROP COLUMN amended_at,
+  DROP COLUMN version;
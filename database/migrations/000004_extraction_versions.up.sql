@@ -0,0 +1,26 @@
+ALTER TABLE extractions
+  ADD COLUMN version integer NOT NULL DEFAULT 1,
+  ADD COLUMN amended_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS extraction_versions (
+  extraction_id text NOT NULL REFERENCES extractions (id) ON DELETE CASCADE,
+  version integer NOT NULL,
+  site_content text,
+  artifact_link text,
+  raw_page_link text,
+  metadata jsonb,
+  language text NOT NULL,
+  page_hash text,
+  created_at timestamptz NOT NULL,
+  superseded_at timestamptz NOT NULL,
+  PRIMARY KEY (extraction_id, version)
+);
+
+COMMENT ON COLUMN extraction_versions.created_at IS 'when the version was first scraped';
+
+-- An extraction is amended when the hash of its page changes. The hashes stored so far were taken over the HTML
+-- of the page, which differs between the fetchers, and cannot be compared: an extraction without a hash is never
+-- considered amended and gets the text hash on its next scrape.
+UPDATE extractions SET page_hash = NULL;
+
+COMMENT ON COLUMN extractions.page_hash IS 'sha256 of the text of #divJudgement with its whitespace collapsed';
@@ -0,0 +1,5 @@
+ALTER TABLE extractions
+  DROP COLUMN raw_page_size,
+  DROP COLUMN raw_page_sha256,
+  DROP COLUMN artifact_size,
+  DROP COLUMN artifact_sha256;
@@ -0,0 +1,5 @@
+ALTER TABLE extractions
+  ADD COLUMN artifact_sha256 text,
+  ADD COLUMN artifact_size bigint,
+  ADD COLUMN raw_page_sha256 text,
+  ADD COLUMN raw_page_size bigint;
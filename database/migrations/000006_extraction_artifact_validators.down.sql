@@ -0,0 +1,5 @@
+ALTER TABLE extractions
+  DROP COLUMN raw_page_last_modified,
+  DROP COLUMN raw_page_etag,
+  DROP COLUMN artifact_last_modified,
+  DROP COLUMN artifact_etag;
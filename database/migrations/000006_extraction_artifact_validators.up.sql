@@ -0,0 +1,5 @@
+ALTER TABLE extractions
+  ADD COLUMN artifact_etag text,
+  ADD COLUMN artifact_last_modified text,
+  ADD COLUMN raw_page_etag text,
+  ADD COLUMN raw_page_last_modified text;
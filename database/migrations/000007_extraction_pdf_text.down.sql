@@ -0,0 +1,4 @@
+ALTER TABLE extractions
+  DROP COLUMN pdf_paragraph_pages,
+  DROP COLUMN pdf_page_count,
+  DROP COLUMN pdf_text;
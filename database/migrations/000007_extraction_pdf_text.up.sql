@@ -0,0 +1,6 @@
+ALTER TABLE extractions
+  ADD COLUMN pdf_text text,
+  ADD COLUMN pdf_page_count integer,
+  ADD COLUMN pdf_paragraph_pages jsonb;
+
+COMMENT ON COLUMN extractions.pdf_text IS 'text layer of the judgement PDF, pages separated by form feeds';
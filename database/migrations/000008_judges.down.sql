@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS extractions_metadata_coram_idx;
+
+DROP TABLE IF EXISTS judges;
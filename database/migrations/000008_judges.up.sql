@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS judges (
+  id text PRIMARY KEY,
+  name text NOT NULL,
+  honorific text NOT NULL,
+  role text NOT NULL,
+  first_decision_date timestamptz,
+  last_decision_date timestamptz,
+  created_at timestamptz NOT NULL,
+  updated_at timestamptz NOT NULL
+);
+
+COMMENT ON COLUMN judges.id IS 'canonical name, the id of the judge in extractions.metadata -> coram';
+COMMENT ON COLUMN judges.name IS 'name as written in the latest judgement';
+
+CREATE INDEX IF NOT EXISTS extractions_metadata_coram_idx
+  ON extractions USING gin ((metadata -> 'coram') jsonb_path_ops);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS extractions_metadata_citation_slug_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS extractions_metadata_citation_slug_idx
+  ON extractions ((metadata -> 'citation' ->> 'slug'));
@@ -0,0 +1,123 @@
+// Package documenttest records pages of the site, serves them and compares extraction results against golden files,
+// so the parsers can be tested offline.
+package documenttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+)
+
+var (
+	update = flag.Bool("update", false, "rewrite the golden files with the current results")
+	record = flag.Bool("record", false, "download the recorded pages from the site again")
+	// recorded fails the tests still run against hand-written stand-in pages
+	recorded = flag.Bool("recorded", false, "fail when a page was not recorded from the site")
+)
+
+// recordedPrefix starts every page written by Record.
+const recordedPrefix = "<!-- Recorded from "
+
+// Record downloads url into dir/name when the tests run with -record, and does nothing otherwise.
+// The page is trimmed of its scripts, styles and embedded frames, the markup read by the parsers is kept as served.
+// With -recorded, a page of dir/name that was not written by Record fails the test.
+func Record(t testing.TB, dir string, name string, url string) {
+	t.Helper()
+	if !*record {
+		if *recorded {
+			assertRecorded(t, filepath.Join(dir, name), url)
+		}
+		return
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to record %s: %v", url, err)
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; singapore-supreme-court-crawler test fixtures)")
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to record %s: %v", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("failed to record %s: unexpected status %d", url, res.StatusCode)
+	}
+
+	doc, err := gq.NewDocumentFromReader(res.Body)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", url, err)
+	}
+	doc.Find("script, style, noscript, iframe, svg, link[rel=stylesheet]").Remove()
+	html, err := doc.Html()
+	if err != nil {
+		t.Fatalf("failed to render %s: %v", url, err)
+	}
+
+	page := fmt.Sprintf("%s%s on %s -->\n%s\n", recordedPrefix, url, time.Now().UTC().Format(time.DateOnly), html)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(page), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func assertRecorded(t testing.TB, path string, url string) {
+	t.Helper()
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if !bytes.HasPrefix(page, []byte(recordedPrefix)) {
+		t.Fatalf("%s is a stand-in page, record %s with make record-fixtures", path, url)
+	}
+}
+
+// NewServer serves the files of dir, a page recorded as dir/listing.html is found at server.URL + "/listing.html".
+// The server is closed when the test ends.
+func NewServer(t testing.TB, dir string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// AssertGolden compares the indented JSON encoding of got with the golden file at path.
+// With -update the golden file is rewritten instead.
+func AssertGolden(t testing.TB, path string, got any) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	actual = append(actual, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the test with -update to create it: %v", err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("result differs from %s\n--- got\n%s\n--- want\n%s", path, actual, expected)
+	}
+}
@@ -0,0 +1,57 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+)
+
+// goqueryNode is a Node parsed from HTML with goquery.
+type goqueryNode struct {
+	s *gq.Selection
+}
+
+// FromSelection wraps the first element of a goquery selection.
+func FromSelection(s *gq.Selection) Node {
+	return goqueryNode{s: s.First()}
+}
+
+// Parse parses an HTML page or fragment into its document node.
+func Parse(html string) (Node, error) {
+	doc, err := gq.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
+	return FromSelection(doc.Selection), nil
+}
+
+func (n goqueryNode) Find(selector string) (Node, error) {
+	found := n.s.Find(selector).First()
+	if found.Length() == 0 {
+		return nil, notFound(selector)
+	}
+	return goqueryNode{s: found}, nil
+}
+
+func (n goqueryNode) FindAll(selector string) ([]Node, error) {
+	found := n.s.Find(selector)
+	nodes := make([]Node, 0, found.Length())
+	found.Each(func(_ int, s *gq.Selection) {
+		nodes = append(nodes, goqueryNode{s: s})
+	})
+	return nodes, nil
+}
+
+func (n goqueryNode) Text() (string, error) {
+	return InnerText(n.s), nil
+}
+
+func (n goqueryNode) Attr(name string) (string, bool, error) {
+	value, ok := n.s.Attr(name)
+	return value, ok, nil
+}
+
+func (n goqueryNode) HTML() (string, error) {
+	return gq.OuterHtml(n.s)
+}
@@ -0,0 +1,46 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"net/http"
+
+	gq "github.com/PuerkitoBio/goquery"
+)
+
+// HttpFetcher downloads pages over plain HTTP and parses them with goquery, without a browser.
+// It only sees the HTML served by elitigation.sg, which holds the listings and judgements without running scripts.
+type HttpFetcher struct {
+	client *http.Client
+}
+
+// NewHttpFetcher downloads pages with client, see NewHttpClient for one that sets the User-Agent and paces the requests.
+func NewHttpFetcher(client *http.Client) *HttpFetcher {
+	return &HttpFetcher{client: client}
+}
+
+// Fetch downloads url and parses the response into a document.
+func (f *HttpFetcher) Fetch(ctx context.Context, url string) (*gq.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, retry.WithStatus(res.StatusCode, fmt.Errorf("unexpected status %d fetching %s", res.StatusCode, url))
+	}
+
+	doc, err := gq.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", url, err)
+	}
+	return doc, nil
+}
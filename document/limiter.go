@@ -0,0 +1,107 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"net/http"
+	stdUrl "net/url"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+	"golang.org/x/time/rate"
+)
+
+// ErrDisallowed is returned by Limiter.Wait for a url the robots.txt of its site disallows.
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// Limiter paces the requests made to a site with a token bucket shared by every browser page and HTTP client,
+// and refuses the urls disallowed by the site's robots.txt once it is loaded.
+type Limiter struct {
+	limiter *rate.Limiter
+
+	mu     sync.RWMutex
+	host   string
+	robots *robotstxt.Group
+}
+
+// NewLimiter allows one request every interval, with bursts of up to burst requests.
+// A zero interval does not pace the requests.
+func NewLimiter(interval time.Duration, burst int) *Limiter {
+	limit := rate.Inf
+	if interval > 0 {
+		limit = rate.Every(interval)
+	}
+	return &Limiter{limiter: rate.NewLimiter(limit, max(burst, 1))}
+}
+
+// LoadRobots downloads the robots.txt of siteUrl and applies the rules of the group matching agent to its host.
+// A Crawl-delay longer than the interval of the limiter slows it down.
+// A missing robots.txt allows everything, a server error fails as nothing may be crawled until it is back.
+func (l *Limiter) LoadRobots(ctx context.Context, client *http.Client, siteUrl string, agent string) error {
+	u, err := stdUrl.Parse(siteUrl)
+	if err != nil {
+		return fmt.Errorf("invalid site url %s: %w", siteUrl, err)
+	}
+	robotsUrl := u.ResolveReference(&stdUrl.URL{Path: "/robots.txt"}).String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsUrl, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", robotsUrl, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("robots.txt unavailable, status %d fetching %s", res.StatusCode, robotsUrl)
+	}
+
+	robots, err := robotstxt.FromResponse(res)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", robotsUrl, err)
+	}
+	group := robots.FindGroup(agent)
+
+	l.mu.Lock()
+	l.host = u.Host
+	l.robots = group
+	l.mu.Unlock()
+
+	if group.CrawlDelay > 0 && rate.Every(group.CrawlDelay) < l.limiter.Limit() {
+		l.limiter.SetLimit(rate.Every(group.CrawlDelay))
+	}
+	return nil
+}
+
+// Wait blocks until url may be requested.
+// It fails without waiting when robots.txt disallows url.
+func (l *Limiter) Wait(ctx context.Context, url string) error {
+	if err := l.allowed(url); err != nil {
+		return err
+	}
+	return l.limiter.Wait(ctx)
+}
+
+func (l *Limiter) allowed(url string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.robots == nil {
+		return nil
+	}
+	u, err := stdUrl.Parse(url)
+	if err != nil {
+		return fmt.Errorf("invalid url %s: %w", url, err)
+	}
+	if u.Host != l.host {
+		return nil
+	}
+	if !l.robots.Test(u.RequestURI()) {
+		return retry.Permanent(fmt.Errorf("%w: %s", ErrDisallowed, url))
+	}
+	return nil
+}
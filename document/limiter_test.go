@@ -0,0 +1,63 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterRobots(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte("User-agent: *\nDisallow: /\n\nUser-agent: test-crawler\nDisallow: /private/\nCrawl-delay: 2\n"))
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(time.Second, 1)
+	client := NewHttpClient(time.Second, "test-crawler/1.0", limiter)
+	if err := limiter.LoadRobots(context.Background(), client, server.URL, "test-crawler"); err != nil {
+		t.Fatalf("LoadRobots() error = %v", err)
+	}
+	if userAgent != "test-crawler/1.0" {
+		t.Errorf("robots.txt requested with User-Agent %q", userAgent)
+	}
+	if limiter.limiter.Limit() != rate.Every(2*time.Second) {
+		t.Errorf("limit = %v, want the crawl delay of robots.txt", limiter.limiter.Limit())
+	}
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: server.URL + "/gd/s/2024_SGHC_112", allowed: true},
+		{url: server.URL + "/private/judgement", allowed: false},
+		{url: "https://example.com/private/judgement", allowed: true},
+	}
+	for _, tt := range tests {
+		err := limiter.allowed(tt.url)
+		if tt.allowed && err != nil {
+			t.Errorf("allowed(%s) error = %v", tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrDisallowed) {
+			t.Errorf("allowed(%s) error = %v, want ErrDisallowed", tt.url, err)
+		}
+	}
+}
+
+func TestLimiterRobotsServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(0, 1)
+	if err := limiter.LoadRobots(context.Background(), server.Client(), server.URL, "test-crawler"); err == nil {
+		t.Fatal("LoadRobots() error = nil, want an error while robots.txt is unavailable")
+	}
+}
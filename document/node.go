@@ -0,0 +1,29 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/retry"
+)
+
+// ErrNotFound is returned by Node.Find when no element matches the selector, as a parse error.
+var ErrNotFound = errors.New("element not found")
+
+// Node is an element of a page, loaded in the browser or parsed from HTML.
+// The extraction logic only depends on Node, so it runs the same on rod pages, plain HTTP responses and fixture files.
+type Node interface {
+	// Find returns the first descendant matching the CSS selector, an error wrapping ErrNotFound when there is none.
+	Find(selector string) (Node, error)
+	// FindAll returns every descendant matching the CSS selector, none is not an error.
+	FindAll(selector string) ([]Node, error)
+	// Text returns the text of the element as rendered by a browser.
+	Text() (string, error)
+	// Attr returns the value of an attribute and whether the element has it.
+	Attr(name string) (string, bool, error)
+	// HTML returns the outer HTML of the element.
+	HTML() (string, error)
+}
+
+func notFound(selector string) error {
+	return retry.Parse(fmt.Errorf("%w: %s", ErrNotFound, selector))
+}
@@ -0,0 +1,70 @@
+package document
+
+import (
+	"github.com/go-rod/rod"
+)
+
+// rodNode is a Node of a page loaded in the browser.
+type rodNode struct {
+	e *rod.Element
+}
+
+// FromElement wraps an element of a rod page.
+func FromElement(e *rod.Element) Node {
+	return rodNode{e: e}
+}
+
+// FromPage returns the document element of a rod page.
+func FromPage(page *rod.Page) (Node, error) {
+	elements, err := page.Elements("html")
+	if err != nil {
+		return nil, err
+	}
+	if elements.Empty() {
+		return nil, notFound("html")
+	}
+	return rodNode{e: elements.First()}, nil
+}
+
+// Find does not wait for the selector to appear, unlike rod's Element, the page is expected to be loaded.
+func (n rodNode) Find(selector string) (Node, error) {
+	elements, err := n.e.Elements(selector)
+	if err != nil {
+		return nil, err
+	}
+	if elements.Empty() {
+		return nil, notFound(selector)
+	}
+	return rodNode{e: elements.First()}, nil
+}
+
+func (n rodNode) FindAll(selector string) ([]Node, error) {
+	elements, err := n.e.Elements(selector)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, len(elements))
+	for i, e := range elements {
+		nodes[i] = rodNode{e: e}
+	}
+	return nodes, nil
+}
+
+func (n rodNode) Text() (string, error) {
+	return n.e.Text()
+}
+
+func (n rodNode) Attr(name string) (string, bool, error) {
+	value, err := n.e.Attribute(name)
+	if err != nil {
+		return "", false, err
+	}
+	if value == nil {
+		return "", false, nil
+	}
+	return *value, true, nil
+}
+
+func (n rodNode) HTML() (string, error) {
+	return n.e.HTML()
+}
@@ -0,0 +1,63 @@
+package document
+
+import (
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "footer": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "tbody": true, "tfoot": true, "thead": true,
+	"tr": true, "ul": true,
+}
+
+// InnerText approximates the text the browser renders for a selection, as rod's Text returns it:
+// whitespace is collapsed, block elements and line breaks start a new line and blank lines are dropped.
+func InnerText(s *gq.Selection) string {
+	var b strings.Builder
+	for _, n := range s.Nodes {
+		writeInnerText(&b, n)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeInnerText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "br":
+			b.WriteString("\n")
+			return
+		case "script", "style", "template":
+			return
+		case "td", "th":
+			b.WriteString(" ")
+		}
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.Data]
+	if block {
+		b.WriteString("\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeInnerText(b, c)
+	}
+	if block {
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,40 @@
+package document
+
+import (
+	"net/http"
+	"time"
+)
+
+// politeTransport sets the User-Agent of every request and waits for its turn on the limiter.
+type politeTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	limiter   *Limiter
+}
+
+// NewHttpClient returns a client identifying itself with userAgent whose requests are paced by limiter.
+// A nil limiter does not pace the requests.
+func NewHttpClient(timeout time.Duration, userAgent string, limiter *Limiter) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &politeTransport{
+			base:      http.DefaultTransport,
+			userAgent: userAgent,
+			limiter:   limiter,
+		},
+	}
+}
+
+func (t *politeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter != nil {
+		if err := t.limiter.Wait(req.Context(), req.URL.String()); err != nil {
+			return nil, err
+		}
+	}
+	if t.userAgent != "" {
+		// A RoundTripper must not modify the request it is given
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
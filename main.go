@@ -2,8 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"lexicon/singapore-supreme-court-crawler/common"
-	"lexicon/singapore-supreme-court-crawler/scrapper"
+	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	scrapper_service "lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/golang-module/carbon/v2"
 
@@ -12,6 +21,8 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 func main() {
@@ -29,44 +40,184 @@ func main() {
 		Locale:       "en",
 	})
 
-	// INITIATE DATABASES
-	// PGSQL
-	ctx := context.Background()
+	// RESOLVE COMMAND
+	commands := newCommands(cfg)
 
-	pgsqlClient, err := pgxpool.New(ctx, cfg.PgSql.ConnStr())
+	name, args := defaultCommand, os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		printUsage(commands)
+		return
+	}
+	cmd := findCommand(commands, name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(commands)
+		os.Exit(2)
+	}
+	if err := cmd.Flags.Parse(args); err != nil {
+		os.Exit(2)
+	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = cmd.Run(ctx)
+	stop()
 	if err != nil {
-		log.Error().Err(err).Msg("Unable to connect to PGSQL Database")
+		log.Error().Err(err).Msgf("Command %s failed", cmd.Name)
+		os.Exit(1)
 	}
-	defer pgsqlClient.Close()
+}
 
-	err = common.SetDatabase(pgsqlClient)
+// setupDatabase connects to PGSQL and registers the pool and queries in common.
+// The returned function closes the pool.
+func setupDatabase(ctx context.Context, cfg config) (func(), error) {
+	pgsqlClient, err := pgxpool.New(ctx, cfg.PgSql.ConnStr())
 	if err != nil {
-		log.Error().Err(err).Msg("Unable to set database")
+		return nil, fmt.Errorf("unable to connect to PGSQL database: %w", err)
 	}
 
-	// GCS
-	gcsClient, err := storage.NewClient(ctx)
-	if err != nil {
-		log.Error().Err(err).Msg("Unable to connect to GCS")
+	if err := common.SetDatabase(pgsqlClient); err != nil {
+		pgsqlClient.Close()
+		return nil, fmt.Errorf("unable to set database: %w", err)
+	}
+
+	if err := common.SetQueries(repository.New(pgsqlClient)); err != nil {
+		pgsqlClient.Close()
+		return nil, fmt.Errorf("unable to set queries: %w", err)
+	}
+
+	return pgsqlClient.Close, nil
+}
+
+// setupStorage creates the artifact store of the configured backend and registers it in common.
+// The returned function closes its client.
+func setupStorage(ctx context.Context, cfg config) (func(), error) {
+	switch cfg.Storage.Backend {
+	case STORAGE_BACKEND_GCS:
+		gcsClient, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to GCS: %w", err)
+		}
+
+		if err := common.SetArtifactStore(scrapper_service.NewGcsArtifactStore(gcsClient, cfg.Storage.GcsBucket)); err != nil {
+			gcsClient.Close()
+			return nil, fmt.Errorf("unable to set artifact store: %w", err)
+		}
+
+		return func() {
+			if err := gcsClient.Close(); err != nil {
+				log.Error().Err(err).Msg("Error closing GCS client")
+			}
+		}, nil
+
+	case STORAGE_BACKEND_LOCAL:
+		store, err := scrapper_service.NewLocalArtifactStore(cfg.Storage.LocalDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := common.SetArtifactStore(store); err != nil {
+			return nil, fmt.Errorf("unable to set artifact store: %w", err)
+		}
+		return func() {}, nil
+
+	case STORAGE_BACKEND_S3:
+		if cfg.Storage.S3Endpoint == "" {
+			return nil, errors.New("S3_ENDPOINT is not set")
+		}
+		store, err := scrapper_service.NewS3ArtifactStore(ctx, scrapper_service.S3Options{
+			Endpoint:        cfg.Storage.S3Endpoint,
+			AccessKeyId:     cfg.Storage.S3AccessKeyId,
+			SecretAccessKey: cfg.Storage.S3SecretAccessKey,
+			Region:          cfg.Storage.S3Region,
+			Bucket:          cfg.Storage.S3Bucket,
+			UseSsl:          cfg.Storage.S3UseSsl,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to S3: %w", err)
+		}
+		if err := common.SetArtifactStore(store); err != nil {
+			return nil, fmt.Errorf("unable to set artifact store: %w", err)
+		}
+		return func() {}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, expected gcs, local or s3", cfg.Storage.Backend)
+	}
+}
+
+// setupFetch creates the rate limiter and HTTP client shared by every request to the site and registers them in common.
+// With robots enabled, the site's robots.txt is loaded first.
+func setupFetch(ctx context.Context, cfg config) error {
+	limiter := document.NewLimiter(cfg.Fetch.Interval, int(cfg.Fetch.Burst))
+	client := document.NewHttpClient(cfg.Fetch.Timeout, cfg.Fetch.UserAgent, limiter)
+
+	if cfg.Fetch.Robots {
+		if err := limiter.LoadRobots(ctx, client, fmt.Sprintf("https://%s", common.CRAWLER_DOMAIN), common.CRAWLER_NAME); err != nil {
+			return fmt.Errorf("unable to load robots.txt: %w", err)
+		}
 	}
 
-	defer gcsClient.Close()
+	if err := common.SetRequestLimiter(limiter); err != nil {
+		return fmt.Errorf("unable to set request limiter: %w", err)
+	}
+	if err := common.SetHttpClient(client); err != nil {
+		return fmt.Errorf("unable to set http client: %w", err)
+	}
+	return nil
+}
 
-	err = common.SetStorageClient(gcsClient)
+// setupJetStream connects to NATS, registers JetStream in common and creates the work queue stream.
+// The returned function drains the connection.
+func setupJetStream(ctx context.Context, cfg config) (func(), error) {
+	if cfg.Nats.Url == "" {
+		return nil, errors.New("NATS_URL is not set")
+	}
+
+	nc, err := nats.Connect(cfg.Nats.Url, nats.Name(common.CRAWLER_NAME))
 	if err != nil {
-		log.Error().Err(err).Msg("Unable to set storage client")
+		return nil, fmt.Errorf("unable to connect to NATS: %w", err)
+	}
 
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("unable to create jetstream: %w", err)
 	}
 
-	// crawler := crawler.CrawlerImpl{}
-	// crawler.Setup()
+	if err := common.SetJetStream(js); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("unable to set jetstream: %w", err)
+	}
 
-	// crawler.CrawlAll(ctx)
+	if err := crawler_service.EnsureStream(ctx); err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("unable to create stream %s: %w", common.NATS_STREAM, err)
+	}
+
+	return func() {
+		if err := nc.Drain(); err != nil {
+			log.Error().Err(err).Msg("Error draining NATS connection")
+		}
+	}, nil
+}
+
+// setupAll initialises every dependency needed by the crawler and scrapper.
+func setupAll(ctx context.Context, cfg config) (func(), error) {
+	closeDatabase, err := setupDatabase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	scrapper := scrapper.ScrapperImpl{}
-	scrapper.Setup()
-	if err := scrapper.ScrapeAll(ctx); err != nil {
-		log.Error().Err(err).Msg("ScrapeAll error")
+	closeStorage, err := setupStorage(ctx, cfg)
+	if err != nil {
+		closeDatabase()
+		return nil, err
 	}
+
+	return func() {
+		closeStorage()
+		closeDatabase()
+	}, nil
 }
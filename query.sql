@@ -28,17 +28,51 @@ SET
   updated_at = $3
 WHERE id = $1;
 
--- name: GetUnscrappedUrlFrontiers :many
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
-FROM url_frontiers
+-- name: LeaseUnscrappedUrlFrontiers :many
+UPDATE url_frontiers
+SET leased_until = @leased_until::timestamptz
+WHERE id IN (
+  SELECT uf.id
+  FROM url_frontiers uf
+  WHERE
+    uf.crawler = @crawler
+    AND (
+      uf.status = @new_status
+      OR (uf.status IN (@error_status, @invalid_artifact_status) AND uf.next_attempt_at <= @now::timestamptz)
+    )
+    AND (uf.leased_until IS NULL OR uf.leased_until <= @now::timestamptz)
+  ORDER BY uf.created_at ASC
+  LIMIT @max_results
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until;
+
+-- name: LeaseUrlFrontier :one
+UPDATE url_frontiers
+SET leased_until = @leased_until::timestamptz
 WHERE
-  crawler = $1
-  AND status = $2
-ORDER BY created_at ASC LIMIT $3;
+  id = @id
+  AND (
+    status = @new_status
+    OR (status IN (@error_status, @invalid_artifact_status) AND next_attempt_at <= @now::timestamptz)
+  )
+  AND (leased_until IS NULL OR leased_until <= @now::timestamptz)
+RETURNING id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until;
+
+-- name: UpdateUrlFrontierAttempt :batchexec
+UPDATE url_frontiers
+SET
+  status = $2,
+  attempts = $3,
+  last_error = $4,
+  next_attempt_at = $5,
+  leased_until = NULL,
+  updated_at = $6
+WHERE id = $1;
 
 -- name: UpsertExtraction :batchexec
-INSERT INTO extractions (id, url_frontier_id, site_content, artifact_link, raw_page_link, language, page_hash, metadata, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+INSERT INTO extractions (id, url_frontier_id, site_content, artifact_link, raw_page_link, language, page_hash, metadata, created_at, updated_at, version, amended_at, artifact_sha256, artifact_size, raw_page_sha256, raw_page_size, artifact_etag, artifact_last_modified, raw_page_etag, raw_page_last_modified, pdf_text, pdf_page_count, pdf_paragraph_pages)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 ON CONFLICT (id) DO UPDATE
 SET
   url_frontier_id = $2,
@@ -48,17 +82,143 @@ SET
   language = $6,
   page_hash = $7,
   metadata = $8,
-  updated_at = $9;
+  updated_at = $10,
+  version = $11,
+  amended_at = $12,
+  artifact_sha256 = $13,
+  artifact_size = $14,
+  raw_page_sha256 = $15,
+  raw_page_size = $16,
+  artifact_etag = $17,
+  artifact_last_modified = $18,
+  raw_page_etag = $19,
+  raw_page_last_modified = $20,
+  pdf_text = COALESCE($21, extractions.pdf_text),
+  pdf_page_count = COALESCE($22, extractions.pdf_page_count),
+  pdf_paragraph_pages = COALESCE($23, extractions.pdf_paragraph_pages);
+
+-- name: GetExtractionArtifacts :one
+SELECT artifact_link, artifact_sha256, artifact_size, artifact_etag, artifact_last_modified, raw_page_link, raw_page_sha256, raw_page_size, raw_page_etag, raw_page_last_modified, (pdf_text IS NOT NULL)::boolean AS has_pdf_text
+FROM extractions
+WHERE id = @id;
+
+-- name: GetExtractionHashes :many
+SELECT id, page_hash, version, amended_at
+FROM extractions
+WHERE id = ANY(@ids::text[])
+FOR UPDATE;
+
+-- name: ArchiveExtraction :batchexec
+INSERT INTO extraction_versions (extraction_id, version, site_content, artifact_link, raw_page_link, metadata, language, page_hash, created_at, superseded_at)
+SELECT id, version, site_content, artifact_link, raw_page_link, metadata, language, page_hash, COALESCE(amended_at, created_at), @superseded_at
+FROM extractions
+WHERE id = @id
+ON CONFLICT (extraction_id, version) DO NOTHING;
 
 
 -- name: GetUrlFrontierByUrl :one
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
 FROM url_frontiers
 WHERE url = $1
 LIMIT 1;
 
 -- name: GetUrlFrontierById :one
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
 FROM url_frontiers
 WHERE id = $1
 LIMIT 1;
+
+-- name: CountUrlFrontiersByStatus :many
+SELECT status, COUNT(*) AS total
+FROM url_frontiers
+WHERE crawler = $1
+GROUP BY status
+ORDER BY status ASC;
+
+-- name: ListExtractions :many
+SELECT e.id, e.url_frontier_id, e.site_content, e.artifact_link, e.raw_page_link, e.metadata, e.language, e.page_hash, e.created_at, e.updated_at, e.version, e.amended_at, e.artifact_sha256, e.artifact_size, e.raw_page_sha256, e.raw_page_size, e.artifact_etag, e.artifact_last_modified, e.raw_page_etag, e.raw_page_last_modified, e.pdf_text, e.pdf_page_count, e.pdf_paragraph_pages
+FROM extractions e
+JOIN url_frontiers uf ON uf.id = e.url_frontier_id
+WHERE
+  uf.crawler = $1
+  AND e.updated_at >= $2
+  AND e.id > $3
+ORDER BY e.id ASC LIMIT $4;
+
+-- name: GetUrlFrontiersByStatus :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  id = ANY(@ids::text[])
+  AND status = @status;
+
+-- name: GetUrlFrontierIds :many
+SELECT id
+FROM url_frontiers
+WHERE id = ANY(@ids::text[]);
+
+-- name: GetCrawlWatermark :one
+SELECT * FROM crawl_watermarks
+WHERE crawler = @crawler AND job_key = @job_key;
+
+-- name: UpsertCrawlWatermark :exec
+INSERT INTO crawl_watermarks (crawler, job_key, last_decision_date, last_crawled_at)
+VALUES (@crawler, @job_key, @last_decision_date, @last_crawled_at)
+ON CONFLICT (crawler, job_key) DO UPDATE SET
+  last_decision_date = GREATEST(crawl_watermarks.last_decision_date, EXCLUDED.last_decision_date),
+  last_crawled_at = EXCLUDED.last_crawled_at;
+
+-- name: ListDueUrlFrontiers :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  crawler = @crawler
+  AND (
+    status = @new_status
+    OR (status IN (@error_status, @invalid_artifact_status) AND next_attempt_at <= @now::timestamptz)
+  )
+  AND (leased_until IS NULL OR leased_until <= @now::timestamptz)
+  AND id > @after_id
+ORDER BY id ASC LIMIT @max_results;
+
+-- name: ListScrappedUrlFrontiers :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  crawler = @crawler
+  AND status = @status
+  AND id > @after_id
+ORDER BY id ASC LIMIT @max_results;
+
+-- name: GetUrlFrontiersByIds :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE id = ANY(@ids::text[]);
+
+-- name: UpdateExtractionMetadata :batchexec
+UPDATE extractions
+SET
+  metadata = @metadata,
+  updated_at = @updated_at
+WHERE id = @id;
+
+-- name: UpdateExtractionPdfText :batchexec
+UPDATE extractions
+SET
+  pdf_text = @pdf_text,
+  pdf_page_count = @pdf_page_count,
+  pdf_paragraph_pages = @pdf_paragraph_pages,
+  updated_at = @updated_at
+WHERE id = @id;
+
+-- name: UpsertJudges :batchexec
+INSERT INTO judges (id, name, honorific, role, first_decision_date, last_decision_date, created_at, updated_at)
+VALUES (@id, @name, @honorific, @role, @first_decision_date, @last_decision_date, @updated_at, @updated_at)
+ON CONFLICT (id) DO UPDATE
+SET
+  name = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.name ELSE judges.name END,
+  honorific = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.honorific ELSE judges.honorific END,
+  role = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.role ELSE judges.role END,
+  first_decision_date = LEAST(judges.first_decision_date, EXCLUDED.first_decision_date),
+  last_decision_date = GREATEST(judges.last_decision_date, EXCLUDED.last_decision_date),
+  updated_at = EXCLUDED.updated_at;
@@ -19,6 +19,240 @@ var (
 	ErrBatchAlreadyClosed = errors.New("batch already closed")
 )
 
+const archiveExtraction = `-- name: ArchiveExtraction :batchexec
+INSERT INTO extraction_versions (extraction_id, version, site_content, artifact_link, raw_page_link, metadata, language, page_hash, created_at, superseded_at)
+SELECT id, version, site_content, artifact_link, raw_page_link, metadata, language, page_hash, COALESCE(amended_at, created_at), $1
+FROM extractions
+WHERE id = $2
+ON CONFLICT (extraction_id, version) DO NOTHING
+`
+
+type ArchiveExtractionBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type ArchiveExtractionParams struct {
+	SupersededAt time.Time
+	ID           string
+}
+
+func (q *Queries) ArchiveExtraction(ctx context.Context, arg []ArchiveExtractionParams) *ArchiveExtractionBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.SupersededAt,
+			a.ID,
+		}
+		batch.Queue(archiveExtraction, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &ArchiveExtractionBatchResults{br, len(arg), false}
+}
+
+func (b *ArchiveExtractionBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *ArchiveExtractionBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateExtractionMetadata = `-- name: UpdateExtractionMetadata :batchexec
+UPDATE extractions
+SET
+  metadata = $1,
+  updated_at = $2
+WHERE id = $3
+`
+
+type UpdateExtractionMetadataBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateExtractionMetadataParams struct {
+	Metadata  scrapperModel.Metadata
+	UpdatedAt time.Time
+	ID        string
+}
+
+func (q *Queries) UpdateExtractionMetadata(ctx context.Context, arg []UpdateExtractionMetadataParams) *UpdateExtractionMetadataBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.Metadata,
+			a.UpdatedAt,
+			a.ID,
+		}
+		batch.Queue(updateExtractionMetadata, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateExtractionMetadataBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateExtractionMetadataBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateExtractionMetadataBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateExtractionPdfText = `-- name: UpdateExtractionPdfText :batchexec
+UPDATE extractions
+SET
+  pdf_text = $1,
+  pdf_page_count = $2,
+  pdf_paragraph_pages = $3,
+  updated_at = $4
+WHERE id = $5
+`
+
+type UpdateExtractionPdfTextBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateExtractionPdfTextParams struct {
+	PdfText           *string
+	PdfPageCount      *int32
+	PdfParagraphPages []scrapperModel.ParagraphPage
+	UpdatedAt         time.Time
+	ID                string
+}
+
+func (q *Queries) UpdateExtractionPdfText(ctx context.Context, arg []UpdateExtractionPdfTextParams) *UpdateExtractionPdfTextBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.PdfText,
+			a.PdfPageCount,
+			a.PdfParagraphPages,
+			a.UpdatedAt,
+			a.ID,
+		}
+		batch.Queue(updateExtractionPdfText, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateExtractionPdfTextBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateExtractionPdfTextBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateExtractionPdfTextBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateUrlFrontierAttempt = `-- name: UpdateUrlFrontierAttempt :batchexec
+UPDATE url_frontiers
+SET
+  status = $2,
+  attempts = $3,
+  last_error = $4,
+  next_attempt_at = $5,
+  leased_until = NULL,
+  updated_at = $6
+WHERE id = $1
+`
+
+type UpdateUrlFrontierAttemptBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateUrlFrontierAttemptParams struct {
+	ID            string
+	Status        int16
+	Attempts      int32
+	LastError     *string
+	NextAttemptAt *time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpdateUrlFrontierAttempt(ctx context.Context, arg []UpdateUrlFrontierAttemptParams) *UpdateUrlFrontierAttemptBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.Status,
+			a.Attempts,
+			a.LastError,
+			a.NextAttemptAt,
+			a.UpdatedAt,
+		}
+		batch.Queue(updateUrlFrontierAttempt, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateUrlFrontierAttemptBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateUrlFrontierAttemptBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateUrlFrontierAttemptBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
 const updateUrlFrontierStatus = `-- name: UpdateUrlFrontierStatus :batchexec
 UPDATE url_frontiers
 SET
@@ -75,8 +309,8 @@ func (b *UpdateUrlFrontierStatusBatchResults) Close() error {
 }
 
 const upsertExtraction = `-- name: UpsertExtraction :batchexec
-INSERT INTO extractions (id, url_frontier_id, site_content, artifact_link, raw_page_link, language, page_hash, metadata, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+INSERT INTO extractions (id, url_frontier_id, site_content, artifact_link, raw_page_link, language, page_hash, metadata, created_at, updated_at, version, amended_at, artifact_sha256, artifact_size, raw_page_sha256, raw_page_size, artifact_etag, artifact_last_modified, raw_page_etag, raw_page_last_modified, pdf_text, pdf_page_count, pdf_paragraph_pages)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 ON CONFLICT (id) DO UPDATE
 SET
   url_frontier_id = $2,
@@ -86,7 +320,20 @@ SET
   language = $6,
   page_hash = $7,
   metadata = $8,
-  updated_at = $9
+  updated_at = $10,
+  version = $11,
+  amended_at = $12,
+  artifact_sha256 = $13,
+  artifact_size = $14,
+  raw_page_sha256 = $15,
+  raw_page_size = $16,
+  artifact_etag = $17,
+  artifact_last_modified = $18,
+  raw_page_etag = $19,
+  raw_page_last_modified = $20,
+  pdf_text = COALESCE($21, extractions.pdf_text),
+  pdf_page_count = COALESCE($22, extractions.pdf_page_count),
+  pdf_paragraph_pages = COALESCE($23, extractions.pdf_paragraph_pages)
 `
 
 type UpsertExtractionBatchResults struct {
@@ -96,16 +343,29 @@ type UpsertExtractionBatchResults struct {
 }
 
 type UpsertExtractionParams struct {
-	ID            string
-	UrlFrontierID string
-	SiteContent   *string
-	ArtifactLink  *string
-	RawPageLink   *string
-	Language      string
-	PageHash      *string
-	Metadata      scrapperModel.Metadata
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                   string
+	UrlFrontierID        string
+	SiteContent          *string
+	ArtifactLink         *string
+	RawPageLink          *string
+	Language             string
+	PageHash             *string
+	Metadata             scrapperModel.Metadata
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Version              int32
+	AmendedAt            *time.Time
+	ArtifactSha256       *string
+	ArtifactSize         *int64
+	RawPageSha256        *string
+	RawPageSize          *int64
+	ArtifactEtag         *string
+	ArtifactLastModified *string
+	RawPageEtag          *string
+	RawPageLastModified  *string
+	PdfText              *string
+	PdfPageCount         *int32
+	PdfParagraphPages    []scrapperModel.ParagraphPage
 }
 
 func (q *Queries) UpsertExtraction(ctx context.Context, arg []UpsertExtractionParams) *UpsertExtractionBatchResults {
@@ -122,6 +382,19 @@ func (q *Queries) UpsertExtraction(ctx context.Context, arg []UpsertExtractionPa
 			a.Metadata,
 			a.CreatedAt,
 			a.UpdatedAt,
+			a.Version,
+			a.AmendedAt,
+			a.ArtifactSha256,
+			a.ArtifactSize,
+			a.RawPageSha256,
+			a.RawPageSize,
+			a.ArtifactEtag,
+			a.ArtifactLastModified,
+			a.RawPageEtag,
+			a.RawPageLastModified,
+			a.PdfText,
+			a.PdfPageCount,
+			a.PdfParagraphPages,
 		}
 		batch.Queue(upsertExtraction, vals...)
 	}
@@ -150,6 +423,74 @@ func (b *UpsertExtractionBatchResults) Close() error {
 	return b.br.Close()
 }
 
+const upsertJudges = `-- name: UpsertJudges :batchexec
+INSERT INTO judges (id, name, honorific, role, first_decision_date, last_decision_date, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+ON CONFLICT (id) DO UPDATE
+SET
+  name = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.name ELSE judges.name END,
+  honorific = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.honorific ELSE judges.honorific END,
+  role = CASE WHEN judges.last_decision_date IS NULL OR EXCLUDED.last_decision_date >= judges.last_decision_date THEN EXCLUDED.role ELSE judges.role END,
+  first_decision_date = LEAST(judges.first_decision_date, EXCLUDED.first_decision_date),
+  last_decision_date = GREATEST(judges.last_decision_date, EXCLUDED.last_decision_date),
+  updated_at = EXCLUDED.updated_at
+`
+
+type UpsertJudgesBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpsertJudgesParams struct {
+	ID                string
+	Name              string
+	Honorific         string
+	Role              string
+	FirstDecisionDate *time.Time
+	LastDecisionDate  *time.Time
+	UpdatedAt         time.Time
+}
+
+func (q *Queries) UpsertJudges(ctx context.Context, arg []UpsertJudgesParams) *UpsertJudgesBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.Name,
+			a.Honorific,
+			a.Role,
+			a.FirstDecisionDate,
+			a.LastDecisionDate,
+			a.UpdatedAt,
+		}
+		batch.Queue(upsertJudges, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpsertJudgesBatchResults{br, len(arg), false}
+}
+
+func (b *UpsertJudgesBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpsertJudgesBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
 const upsertUrlFrontiers = `-- name: UpsertUrlFrontiers :batchexec
 INSERT INTO url_frontiers (id, domain, url, crawler, status, metadata, created_at, updated_at)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
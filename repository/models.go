@@ -11,17 +11,65 @@ import (
 	scrapperModel "lexicon/singapore-supreme-court-crawler/scrapper/models"
 )
 
+type CrawlWatermark struct {
+	Crawler          string
+	JobKey           string
+	LastDecisionDate time.Time
+	LastCrawledAt    time.Time
+}
+
 type Extraction struct {
-	ID            string
-	UrlFrontierID string
-	SiteContent   *string
-	ArtifactLink  *string
-	RawPageLink   *string
-	Metadata      scrapperModel.Metadata
-	Language      string
-	PageHash      *string
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                   string
+	UrlFrontierID        string
+	SiteContent          *string
+	ArtifactLink         *string
+	RawPageLink          *string
+	Metadata             scrapperModel.Metadata
+	Language             string
+	PageHash             *string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Version              int32
+	AmendedAt            *time.Time
+	ArtifactSha256       *string
+	ArtifactSize         *int64
+	RawPageSha256        *string
+	RawPageSize          *int64
+	ArtifactEtag         *string
+	ArtifactLastModified *string
+	RawPageEtag          *string
+	RawPageLastModified  *string
+	// text layer of the judgement PDF, pages separated by form feeds
+	PdfText           *string
+	PdfPageCount      *int32
+	PdfParagraphPages []scrapperModel.ParagraphPage
+}
+
+type ExtractionVersion struct {
+	ExtractionID string
+	Version      int32
+	SiteContent  *string
+	ArtifactLink *string
+	RawPageLink  *string
+	Metadata     scrapperModel.Metadata
+	Language     string
+	PageHash     *string
+	// when the version was first scraped
+	CreatedAt    time.Time
+	SupersededAt time.Time
+}
+
+type Judge struct {
+	// canonical name, the id of the judge in extractions.metadata -> coram
+	ID string
+	// name as written in the latest judgement
+	Name              string
+	Honorific         string
+	Role              string
+	FirstDecisionDate *time.Time
+	LastDecisionDate  *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 type UrlFrontier struct {
@@ -30,8 +78,12 @@ type UrlFrontier struct {
 	Url     string
 	Crawler string
 	// 0: Pending, 1: Crawled, 2: Changed
-	Status    int16
-	Metadata  crawlerModel.UrlFrontierMetadata
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Status        int16
+	Metadata      crawlerModel.UrlFrontierMetadata
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Attempts      int32
+	LastError     *string
+	NextAttemptAt *time.Time
+	LeasedUntil   *time.Time
 }
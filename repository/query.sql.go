@@ -12,39 +12,128 @@ import (
 	crawlerModel "lexicon/singapore-supreme-court-crawler/crawler/models"
 )
 
-const getUnscrappedUrlFrontiers = `-- name: GetUnscrappedUrlFrontiers :many
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
+const countUrlFrontiersByStatus = `-- name: CountUrlFrontiersByStatus :many
+SELECT status, COUNT(*) AS total
 FROM url_frontiers
-WHERE
-  crawler = $1
-  AND status = $2
-ORDER BY created_at ASC LIMIT $3
+WHERE crawler = $1
+GROUP BY status
+ORDER BY status ASC
+`
+
+type CountUrlFrontiersByStatusRow struct {
+	Status int16
+	Total  int64
+}
+
+func (q *Queries) CountUrlFrontiersByStatus(ctx context.Context, crawler string) ([]CountUrlFrontiersByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countUrlFrontiersByStatus, crawler)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUrlFrontiersByStatusRow
+	for rows.Next() {
+		var i CountUrlFrontiersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCrawlWatermark = `-- name: GetCrawlWatermark :one
+SELECT crawler, job_key, last_decision_date, last_crawled_at FROM crawl_watermarks
+WHERE crawler = $1 AND job_key = $2
 `
 
-type GetUnscrappedUrlFrontiersParams struct {
+type GetCrawlWatermarkParams struct {
 	Crawler string
-	Status  int16
-	Limit   int32
+	JobKey  string
+}
+
+func (q *Queries) GetCrawlWatermark(ctx context.Context, arg GetCrawlWatermarkParams) (CrawlWatermark, error) {
+	row := q.db.QueryRow(ctx, getCrawlWatermark, arg.Crawler, arg.JobKey)
+	var i CrawlWatermark
+	err := row.Scan(
+		&i.Crawler,
+		&i.JobKey,
+		&i.LastDecisionDate,
+		&i.LastCrawledAt,
+	)
+	return i, err
+}
+
+const getExtractionArtifacts = `-- name: GetExtractionArtifacts :one
+SELECT artifact_link, artifact_sha256, artifact_size, artifact_etag, artifact_last_modified, raw_page_link, raw_page_sha256, raw_page_size, raw_page_etag, raw_page_last_modified, (pdf_text IS NOT NULL)::boolean AS has_pdf_text
+FROM extractions
+WHERE id = $1
+`
+
+type GetExtractionArtifactsRow struct {
+	ArtifactLink         *string
+	ArtifactSha256       *string
+	ArtifactSize         *int64
+	ArtifactEtag         *string
+	ArtifactLastModified *string
+	RawPageLink          *string
+	RawPageSha256        *string
+	RawPageSize          *int64
+	RawPageEtag          *string
+	RawPageLastModified  *string
+	HasPdfText           bool
+}
+
+func (q *Queries) GetExtractionArtifacts(ctx context.Context, id string) (GetExtractionArtifactsRow, error) {
+	row := q.db.QueryRow(ctx, getExtractionArtifacts, id)
+	var i GetExtractionArtifactsRow
+	err := row.Scan(
+		&i.ArtifactLink,
+		&i.ArtifactSha256,
+		&i.ArtifactSize,
+		&i.ArtifactEtag,
+		&i.ArtifactLastModified,
+		&i.RawPageLink,
+		&i.RawPageSha256,
+		&i.RawPageSize,
+		&i.RawPageEtag,
+		&i.RawPageLastModified,
+		&i.HasPdfText,
+	)
+	return i, err
 }
 
-func (q *Queries) GetUnscrappedUrlFrontiers(ctx context.Context, arg GetUnscrappedUrlFrontiersParams) ([]UrlFrontier, error) {
-	rows, err := q.db.Query(ctx, getUnscrappedUrlFrontiers, arg.Crawler, arg.Status, arg.Limit)
+const getExtractionHashes = `-- name: GetExtractionHashes :many
+SELECT id, page_hash, version, amended_at
+FROM extractions
+WHERE id = ANY($1::text[])
+FOR UPDATE
+`
+
+type GetExtractionHashesRow struct {
+	ID        string
+	PageHash  *string
+	Version   int32
+	AmendedAt *time.Time
+}
+
+func (q *Queries) GetExtractionHashes(ctx context.Context, ids []string) ([]GetExtractionHashesRow, error) {
+	rows, err := q.db.Query(ctx, getExtractionHashes, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UrlFrontier
+	var items []GetExtractionHashesRow
 	for rows.Next() {
-		var i UrlFrontier
+		var i GetExtractionHashesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Domain,
-			&i.Url,
-			&i.Crawler,
-			&i.Status,
-			&i.Metadata,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.PageHash,
+			&i.Version,
+			&i.AmendedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -57,7 +146,7 @@ func (q *Queries) GetUnscrappedUrlFrontiers(ctx context.Context, arg GetUnscrapp
 }
 
 const getUrlFrontierById = `-- name: GetUrlFrontierById :one
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
 FROM url_frontiers
 WHERE id = $1
 LIMIT 1
@@ -75,12 +164,16 @@ func (q *Queries) GetUrlFrontierById(ctx context.Context, id string) (UrlFrontie
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.LeasedUntil,
 	)
 	return i, err
 }
 
 const getUrlFrontierByUrl = `-- name: GetUrlFrontierByUrl :one
-SELECT id, domain, url, crawler, status, metadata, created_at, updated_at
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
 FROM url_frontiers
 WHERE url = $1
 LIMIT 1
@@ -98,10 +191,457 @@ func (q *Queries) GetUrlFrontierByUrl(ctx context.Context, url string) (UrlFront
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const getUrlFrontierIds = `-- name: GetUrlFrontierIds :many
+SELECT id
+FROM url_frontiers
+WHERE id = ANY($1::text[])
+`
+
+func (q *Queries) GetUrlFrontierIds(ctx context.Context, ids []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getUrlFrontierIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUrlFrontiersByIds = `-- name: GetUrlFrontiersByIds :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE id = ANY($1::text[])
+`
+
+func (q *Queries) GetUrlFrontiersByIds(ctx context.Context, ids []string) ([]UrlFrontier, error) {
+	rows, err := q.db.Query(ctx, getUrlFrontiersByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UrlFrontier
+	for rows.Next() {
+		var i UrlFrontier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Url,
+			&i.Crawler,
+			&i.Status,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUrlFrontiersByStatus = `-- name: GetUrlFrontiersByStatus :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  id = ANY($1::text[])
+  AND status = $2
+`
+
+type GetUrlFrontiersByStatusParams struct {
+	Ids    []string
+	Status int16
+}
+
+func (q *Queries) GetUrlFrontiersByStatus(ctx context.Context, arg GetUrlFrontiersByStatusParams) ([]UrlFrontier, error) {
+	rows, err := q.db.Query(ctx, getUrlFrontiersByStatus, arg.Ids, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UrlFrontier
+	for rows.Next() {
+		var i UrlFrontier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Url,
+			&i.Crawler,
+			&i.Status,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const leaseUnscrappedUrlFrontiers = `-- name: LeaseUnscrappedUrlFrontiers :many
+UPDATE url_frontiers
+SET leased_until = $1::timestamptz
+WHERE id IN (
+  SELECT uf.id
+  FROM url_frontiers uf
+  WHERE
+    uf.crawler = $2
+    AND (
+      uf.status = $3
+      OR (uf.status IN ($4, $5) AND uf.next_attempt_at <= $6::timestamptz)
+    )
+    AND (uf.leased_until IS NULL OR uf.leased_until <= $6::timestamptz)
+  ORDER BY uf.created_at ASC
+  LIMIT $7
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+`
+
+type LeaseUnscrappedUrlFrontiersParams struct {
+	LeasedUntil           time.Time
+	Crawler               string
+	NewStatus             int16
+	ErrorStatus           int16
+	InvalidArtifactStatus int16
+	Now                   time.Time
+	MaxResults            int32
+}
+
+func (q *Queries) LeaseUnscrappedUrlFrontiers(ctx context.Context, arg LeaseUnscrappedUrlFrontiersParams) ([]UrlFrontier, error) {
+	rows, err := q.db.Query(ctx, leaseUnscrappedUrlFrontiers,
+		arg.LeasedUntil,
+		arg.Crawler,
+		arg.NewStatus,
+		arg.ErrorStatus,
+		arg.InvalidArtifactStatus,
+		arg.Now,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UrlFrontier
+	for rows.Next() {
+		var i UrlFrontier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Url,
+			&i.Crawler,
+			&i.Status,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExtractions = `-- name: ListExtractions :many
+SELECT e.id, e.url_frontier_id, e.site_content, e.artifact_link, e.raw_page_link, e.metadata, e.language, e.page_hash, e.created_at, e.updated_at, e.version, e.amended_at, e.artifact_sha256, e.artifact_size, e.raw_page_sha256, e.raw_page_size, e.artifact_etag, e.artifact_last_modified, e.raw_page_etag, e.raw_page_last_modified, e.pdf_text, e.pdf_page_count, e.pdf_paragraph_pages
+FROM extractions e
+JOIN url_frontiers uf ON uf.id = e.url_frontier_id
+WHERE
+  uf.crawler = $1
+  AND e.updated_at >= $2
+  AND e.id > $3
+ORDER BY e.id ASC LIMIT $4
+`
+
+type ListExtractionsParams struct {
+	Crawler   string
+	UpdatedAt time.Time
+	ID        string
+	Limit     int32
+}
+
+func (q *Queries) ListExtractions(ctx context.Context, arg ListExtractionsParams) ([]Extraction, error) {
+	rows, err := q.db.Query(ctx, listExtractions,
+		arg.Crawler,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Extraction
+	for rows.Next() {
+		var i Extraction
+		if err := rows.Scan(
+			&i.ID,
+			&i.UrlFrontierID,
+			&i.SiteContent,
+			&i.ArtifactLink,
+			&i.RawPageLink,
+			&i.Metadata,
+			&i.Language,
+			&i.PageHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.AmendedAt,
+			&i.ArtifactSha256,
+			&i.ArtifactSize,
+			&i.RawPageSha256,
+			&i.RawPageSize,
+			&i.ArtifactEtag,
+			&i.ArtifactLastModified,
+			&i.RawPageEtag,
+			&i.RawPageLastModified,
+			&i.PdfText,
+			&i.PdfPageCount,
+			&i.PdfParagraphPages,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const leaseUrlFrontier = `-- name: LeaseUrlFrontier :one
+UPDATE url_frontiers
+SET leased_until = $1::timestamptz
+WHERE
+  id = $2
+  AND (
+    status = $3
+    OR (status IN ($4, $5) AND next_attempt_at <= $6::timestamptz)
+  )
+  AND (leased_until IS NULL OR leased_until <= $6::timestamptz)
+RETURNING id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+`
+
+type LeaseUrlFrontierParams struct {
+	LeasedUntil           time.Time
+	ID                    string
+	NewStatus             int16
+	ErrorStatus           int16
+	InvalidArtifactStatus int16
+	Now                   time.Time
+}
+
+func (q *Queries) LeaseUrlFrontier(ctx context.Context, arg LeaseUrlFrontierParams) (UrlFrontier, error) {
+	row := q.db.QueryRow(ctx, leaseUrlFrontier,
+		arg.LeasedUntil,
+		arg.ID,
+		arg.NewStatus,
+		arg.ErrorStatus,
+		arg.InvalidArtifactStatus,
+		arg.Now,
+	)
+	var i UrlFrontier
+	err := row.Scan(
+		&i.ID,
+		&i.Domain,
+		&i.Url,
+		&i.Crawler,
+		&i.Status,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.LeasedUntil,
 	)
 	return i, err
 }
 
+const listDueUrlFrontiers = `-- name: ListDueUrlFrontiers :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  crawler = $1
+  AND (
+    status = $2
+    OR (status IN ($3, $4) AND next_attempt_at <= $5::timestamptz)
+  )
+  AND (leased_until IS NULL OR leased_until <= $5::timestamptz)
+  AND id > $6
+ORDER BY id ASC LIMIT $7
+`
+
+type ListDueUrlFrontiersParams struct {
+	Crawler               string
+	NewStatus             int16
+	ErrorStatus           int16
+	InvalidArtifactStatus int16
+	Now                   time.Time
+	AfterID               string
+	MaxResults            int32
+}
+
+func (q *Queries) ListDueUrlFrontiers(ctx context.Context, arg ListDueUrlFrontiersParams) ([]UrlFrontier, error) {
+	rows, err := q.db.Query(ctx, listDueUrlFrontiers,
+		arg.Crawler,
+		arg.NewStatus,
+		arg.ErrorStatus,
+		arg.InvalidArtifactStatus,
+		arg.Now,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UrlFrontier
+	for rows.Next() {
+		var i UrlFrontier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Url,
+			&i.Crawler,
+			&i.Status,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScrappedUrlFrontiers = `-- name: ListScrappedUrlFrontiers :many
+SELECT id, domain, url, crawler, status, metadata, created_at, updated_at, attempts, last_error, next_attempt_at, leased_until
+FROM url_frontiers
+WHERE
+  crawler = $1
+  AND status = $2
+  AND id > $3
+ORDER BY id ASC LIMIT $4
+`
+
+type ListScrappedUrlFrontiersParams struct {
+	Crawler    string
+	Status     int16
+	AfterID    string
+	MaxResults int32
+}
+
+func (q *Queries) ListScrappedUrlFrontiers(ctx context.Context, arg ListScrappedUrlFrontiersParams) ([]UrlFrontier, error) {
+	rows, err := q.db.Query(ctx, listScrappedUrlFrontiers,
+		arg.Crawler,
+		arg.Status,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UrlFrontier
+	for rows.Next() {
+		var i UrlFrontier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Url,
+			&i.Crawler,
+			&i.Status,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertCrawlWatermark = `-- name: UpsertCrawlWatermark :exec
+INSERT INTO crawl_watermarks (crawler, job_key, last_decision_date, last_crawled_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (crawler, job_key) DO UPDATE SET
+  last_decision_date = GREATEST(crawl_watermarks.last_decision_date, EXCLUDED.last_decision_date),
+  last_crawled_at = EXCLUDED.last_crawled_at
+`
+
+type UpsertCrawlWatermarkParams struct {
+	Crawler          string
+	JobKey           string
+	LastDecisionDate time.Time
+	LastCrawledAt    time.Time
+}
+
+func (q *Queries) UpsertCrawlWatermark(ctx context.Context, arg UpsertCrawlWatermarkParams) error {
+	_, err := q.db.Exec(ctx, upsertCrawlWatermark,
+		arg.Crawler,
+		arg.JobKey,
+		arg.LastDecisionDate,
+		arg.LastCrawledAt,
+	)
+	return err
+}
+
 const upsertUrlFrontier = `-- name: UpsertUrlFrontier :exec
 INSERT INTO url_frontiers (id, domain, url, crawler, status, metadata, created_at, updated_at)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -0,0 +1,94 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrorClass tells whether an operation that failed may succeed when tried again.
+type ErrorClass string
+
+const (
+	// ERROR_CLASS_TRANSIENT errors, such as timeouts, connection resets, 429 and 5xx, are retried
+	ERROR_CLASS_TRANSIENT ErrorClass = "transient"
+	// ERROR_CLASS_PERMANENT errors, such as 404 or a url disallowed by robots.txt, fail the same way every time
+	ERROR_CLASS_PERMANENT ErrorClass = "permanent"
+	// ERROR_CLASS_PARSE errors come from a page whose markup the parsers do not understand
+	ERROR_CLASS_PARSE ErrorClass = "parse"
+)
+
+var errorClasses = []ErrorClass{ERROR_CLASS_TRANSIENT, ERROR_CLASS_PERMANENT, ERROR_CLASS_PARSE}
+
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+func mark(class ErrorClass, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// Transient marks err as worth retrying.
+func Transient(err error) error { return mark(ERROR_CLASS_TRANSIENT, err) }
+
+// Permanent marks err as failing the same way on every try.
+func Permanent(err error) error { return mark(ERROR_CLASS_PERMANENT, err) }
+
+// Parse marks err as a page the parsers could not read.
+func Parse(err error) error { return mark(ERROR_CLASS_PARSE, err) }
+
+// WithStatus classifies err by the HTTP status code of the response it comes from.
+func WithStatus(statusCode int, err error) error {
+	return mark(StatusClass(statusCode), err)
+}
+
+// StatusClass is the class of an error response: request timeouts, rate limiting and server errors are transient.
+func StatusClass(statusCode int) ErrorClass {
+	switch {
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooEarly, statusCode == http.StatusTooManyRequests:
+		return ERROR_CLASS_TRANSIENT
+	case statusCode >= http.StatusInternalServerError:
+		return ERROR_CLASS_TRANSIENT
+	default:
+		return ERROR_CLASS_PERMANENT
+	}
+}
+
+// Classify returns the class of err, the one it was marked with or else the one of the network error it wraps.
+// Unknown errors are permanent.
+func Classify(err error) ErrorClass {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ERROR_CLASS_TRANSIENT
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.EOF):
+		return ERROR_CLASS_TRANSIENT
+	default:
+		return ERROR_CLASS_PERMANENT
+	}
+}
+
+// IsTransient reports whether err is worth retrying.
+func IsTransient(err error) bool {
+	return Classify(err) == ERROR_CLASS_TRANSIENT
+}
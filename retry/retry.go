@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Policy tells how often and how long a transient failure is retried within a run.
+// Failures still left after MaxAttempts are scheduled again by the url frontier retry policy.
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// Backoff is a random delay before the retry following the given number of failed tries,
+// up to a cap doubling from BaseDelay to MaxDelay, so concurrent retries do not hit the site together.
+func (p Policy) Backoff(tries int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < tries && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.MaxDelay)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Do calls fn until it succeeds, fails with an error that is not transient, or MaxAttempts tries are made.
+// Each retry is counted in stats under the class of the error that caused it. The last error is returned.
+func Do(ctx context.Context, policy Policy, stats *Stats, name string, fn func(ctx context.Context) error) error {
+	maxAttempts := max(policy.MaxAttempts, 1)
+	for tries := 1; ; tries++ {
+		err := fn(ctx)
+		if err == nil || tries >= maxAttempts || ctx.Err() != nil {
+			return err
+		}
+		class := Classify(err)
+		if class != ERROR_CLASS_TRANSIENT {
+			return err
+		}
+
+		delay := policy.Backoff(tries)
+		stats.retried(class)
+		log.Warn().Err(err).Msgf("Retrying %s in %s, try %d of %d", name, delay.Round(time.Millisecond), tries+1, maxAttempts)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
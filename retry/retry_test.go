@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "too many requests", err: WithStatus(http.StatusTooManyRequests, errors.New("429")), want: ERROR_CLASS_TRANSIENT},
+		{name: "bad gateway", err: WithStatus(http.StatusBadGateway, errors.New("502")), want: ERROR_CLASS_TRANSIENT},
+		{name: "not found", err: WithStatus(http.StatusNotFound, errors.New("404")), want: ERROR_CLASS_PERMANENT},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: ERROR_CLASS_TRANSIENT},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: ERROR_CLASS_TRANSIENT},
+		{name: "deadline", err: context.DeadlineExceeded, want: ERROR_CLASS_TRANSIENT},
+		{name: "parse", err: fmt.Errorf("judgement: %w", Parse(errors.New("no #divJudgement"))), want: ERROR_CLASS_PARSE},
+		{name: "outer mark wins", err: Parse(Transient(errors.New("timeout"))), want: ERROR_CLASS_PARSE},
+		{name: "unknown", err: errors.New("boom"), want: ERROR_CLASS_PERMANENT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantTries int
+		wantErr   bool
+	}{
+		{name: "success", errs: []error{nil}, wantTries: 1},
+		{name: "recovers from transient", errs: []error{Transient(errors.New("reset")), nil}, wantTries: 2},
+		{name: "gives up after max attempts", errs: []error{Transient(errors.New("1")), Transient(errors.New("2")), Transient(errors.New("3"))}, wantTries: 3, wantErr: true},
+		{name: "does not retry permanent", errs: []error{Permanent(errors.New("404"))}, wantTries: 1, wantErr: true},
+		{name: "does not retry parse", errs: []error{Parse(errors.New("no table"))}, wantTries: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := NewStats()
+			tries := 0
+			err := Do(context.Background(), policy, stats, tt.name, func(ctx context.Context) error {
+				err := tt.errs[tries]
+				tries++
+				return err
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Do() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tries != tt.wantTries {
+				t.Errorf("Do() tried %d times, want %d", tries, tt.wantTries)
+			}
+			if retries := stats.Summary().Retries[ERROR_CLASS_TRANSIENT]; retries != tt.wantTries-1 {
+				t.Errorf("Do() counted %d retries, want %d", retries, tt.wantTries-1)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+	for tries, limit := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 6: 4 * time.Second} {
+		delay := policy.Backoff(tries)
+		if delay < limit/2 || delay > limit {
+			t.Errorf("Backoff(%d) = %s, want between %s and %s", tries, delay, limit/2, limit)
+		}
+	}
+}
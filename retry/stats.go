@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Stats counts the retries and the final failures of a run per error class.
+// It is safe for concurrent use and a nil Stats counts nothing.
+type Stats struct {
+	mu       sync.Mutex
+	retries  map[ErrorClass]int
+	failures map[ErrorClass]int
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		retries:  map[ErrorClass]int{},
+		failures: map[ErrorClass]int{},
+	}
+}
+
+func (s *Stats) retried(class ErrorClass) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries[class]++
+}
+
+// Failed counts a url that failed for good in this run under the class of err.
+// Urls interrupted by a cancelled run are not counted.
+func (s *Stats) Failed(err error) {
+	if s == nil || err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[Classify(err)]++
+}
+
+// Summary is a snapshot of Stats.
+type Summary struct {
+	Retries  map[ErrorClass]int `json:"retries"`
+	Failures map[ErrorClass]int `json:"failures"`
+}
+
+func (s *Stats) Summary() Summary {
+	summary := Summary{Retries: map[ErrorClass]int{}, Failures: map[ErrorClass]int{}}
+	if s == nil {
+		return summary
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, class := range errorClasses {
+		summary.Retries[class] = s.retries[class]
+		summary.Failures[class] = s.failures[class]
+	}
+	return summary
+}
+
+func (s Summary) String() string {
+	var retries, failures []string
+	for _, class := range errorClasses {
+		retries = append(retries, fmt.Sprintf("%s=%d", class, s.Retries[class]))
+		failures = append(failures, fmt.Sprintf("%s=%d", class, s.Failures[class]))
+	}
+	return fmt.Sprintf("retries %s, failures %s", strings.Join(retries, " "), strings.Join(failures, " "))
+}
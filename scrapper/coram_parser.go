@@ -0,0 +1,78 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"regexp"
+	"strings"
+)
+
+// judgeRoles maps the honorifics written after the names of judges to their office.
+var judgeRoles = map[string]string{
+	"CJ":  "chief_justice",
+	"JCA": "justice_of_the_court_of_appeal",
+	"JA":  "judge_of_appeal",
+	"JAD": "judge_of_the_appellate_division",
+	"J":   "judge",
+	"JC":  "judicial_commissioner",
+	"SJ":  "senior_judge",
+	"IJ":  "international_judge",
+	"SAR": "senior_assistant_registrar",
+	"AR":  "assistant_registrar",
+	"DR":  "deputy_registrar",
+}
+
+var (
+	coramSplitter       = regexp.MustCompile(`\s*[,;\n]\s*|\s+and\s+`)
+	coramRemarkRegex    = regexp.MustCompile(`\s*\([^)]*\)`)
+	judgeHonorificRegex = regexp.MustCompile(`^(.+?)\s+(CJ|JCA|JAD|JA|JC|SJ|IJ|SAR|AR|DR|J)$`)
+	judgePrefixRegex    = regexp.MustCompile(`(?i)^(?:the\s+honourable\s+)?(?:(?:chief\s+)?justice\s+)?`)
+	judgeIdRegex        = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// parseCoram splits the coram into its judges, such as
+// "Sundaresh Menon CJ, Andrew Phang Boon Leong JA and V K Rajah JA".
+// Remarks in brackets, such as "(delivering the judgment of the court)", are left out.
+func parseCoram(coram string) []models.Judge {
+	var judges []models.Judge
+	for _, part := range coramSplitter.Split(coramRemarkRegex.ReplaceAllString(coram, ""), -1) {
+		part = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(part), ".:"))
+		part = judgePrefixRegex.ReplaceAllString(part, "")
+		if part == "" {
+			continue
+		}
+
+		judge := models.Judge{Name: part}
+		if match := judgeHonorificRegex.FindStringSubmatch(part); match != nil {
+			judge.Name, judge.Honorific, judge.Role = match[1], match[2], judgeRoles[match[2]]
+		}
+		judge.ID = judgeId(judge.Name)
+		judges = append(judges, judge)
+	}
+	return judges
+}
+
+// judgeId returns the canonical name of a judge, the name in lower case with its words joined by "-".
+// Initials are merged so that "V K Rajah", "V. K. Rajah" and "VK Rajah" share the id "vk-rajah".
+func judgeId(name string) string {
+	var words []string
+	initials := ""
+	for _, word := range strings.Fields(strings.ReplaceAll(name, ".", " ")) {
+		word = judgeIdRegex.ReplaceAllString(strings.ToLower(word), "")
+		if word == "" {
+			continue
+		}
+		if len(word) == 1 {
+			initials += word
+			continue
+		}
+		if initials != "" {
+			words = append(words, initials)
+			initials = ""
+		}
+		words = append(words, word)
+	}
+	if initials != "" {
+		words = append(words, initials)
+	}
+	return strings.Join(words, "-")
+}
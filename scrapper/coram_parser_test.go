@@ -0,0 +1,62 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseCoram(t *testing.T) {
+	tests := []struct {
+		name  string
+		coram string
+		want  []models.Judge
+	}{
+		{
+			name:  "single judge",
+			coram: "Vincent Hoong J",
+			want:  []models.Judge{{ID: "vincent-hoong", Name: "Vincent Hoong", Honorific: "J", Role: "judge"}},
+		},
+		{
+			name:  "court of appeal",
+			coram: "Sundaresh Menon CJ, Andrew Phang Boon Leong JA and V K Rajah JA",
+			want: []models.Judge{
+				{ID: "sundaresh-menon", Name: "Sundaresh Menon", Honorific: "CJ", Role: "chief_justice"},
+				{ID: "andrew-phang-boon-leong", Name: "Andrew Phang Boon Leong", Honorific: "JA", Role: "judge_of_appeal"},
+				{ID: "vk-rajah", Name: "V K Rajah", Honorific: "JA", Role: "judge_of_appeal"},
+			},
+		},
+		{
+			name:  "remarks and semicolons",
+			coram: "Woo Bih Li JAD (delivering the judgment of the court); Debbie Ong JAD; Audrey Lim J.",
+			want: []models.Judge{
+				{ID: "woo-bih-li", Name: "Woo Bih Li", Honorific: "JAD", Role: "judge_of_the_appellate_division"},
+				{ID: "debbie-ong", Name: "Debbie Ong", Honorific: "JAD", Role: "judge_of_the_appellate_division"},
+				{ID: "audrey-lim", Name: "Audrey Lim", Honorific: "J", Role: "judge"},
+			},
+		},
+		{
+			name:  "registrar and unknown honorific",
+			coram: "Justice Tan Lee Meng, Elton Tan Xue Yang AR",
+			want: []models.Judge{
+				{ID: "tan-lee-meng", Name: "Tan Lee Meng"},
+				{ID: "elton-tan-xue-yang", Name: "Elton Tan Xue Yang", Honorific: "AR", Role: "assistant_registrar"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCoram(tt.coram); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCoram() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJudgeId(t *testing.T) {
+	for _, name := range []string{"V K Rajah", "V. K. Rajah", "VK Rajah", "V K  RAJAH"} {
+		if got := judgeId(name); got != "vk-rajah" {
+			t.Errorf("judgeId(%q) = %s, want vk-rajah", name, got)
+		}
+	}
+}
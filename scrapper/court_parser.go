@@ -0,0 +1,71 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"strings"
+)
+
+// generalDivisionYear is the year the High Court was split into the General and Appellate Divisions.
+const generalDivisionYear = 2021
+
+// courtNames maps the names of the courts in judicial institutions to courts, the most specific first.
+var courtNames = []struct {
+	name  string
+	court models.Court
+}{
+	{"court of appeal", models.COURT_OF_APPEAL},
+	{"appellate division", models.COURT_APPELLATE_DIVISION},
+	{"international commercial court", models.COURT_SICC},
+	{"sicc", models.COURT_SICC},
+	{"family division", models.COURT_FAMILY_DIVISION},
+	{"general division", models.COURT_GENERAL_DIVISION},
+	{"high court", models.COURT_HIGH_COURT},
+}
+
+// classifyCourt sets the court of metadata from its judicial institution and cross-checks it with the court code of its citation.
+// Without a known institution the court is taken from the citation code.
+func classifyCourt(metadata *models.Metadata) {
+	metadata.Court = courtOfInstitution(metadata.JudicalInstitution)
+	metadata.CitationCourt, metadata.CourtMismatch = "", false
+	if metadata.Citation == nil {
+		return
+	}
+	metadata.CitationCourt = metadata.Citation.Court
+
+	if metadata.Court == models.COURT_UNKNOWN {
+		metadata.Court = courtOfCitationCode(metadata.CitationCourt, metadata.Citation.Year)
+		return
+	}
+	metadata.CourtMismatch = !metadata.Court.AcceptsCitationCode(metadata.CitationCourt)
+}
+
+func courtOfInstitution(institution string) models.Court {
+	institution = strings.ToLower(institution)
+	for _, name := range courtNames {
+		if strings.Contains(institution, name.name) {
+			return name.court
+		}
+	}
+	return models.COURT_UNKNOWN
+}
+
+// courtOfCitationCode returns the court whose judgements are cited with code in year.
+func courtOfCitationCode(code string, year int) models.Court {
+	switch code {
+	case "SGCA", "SGCA(I)":
+		return models.COURT_OF_APPEAL
+	case "SGHCA":
+		return models.COURT_APPELLATE_DIVISION
+	case "SGHCF":
+		return models.COURT_FAMILY_DIVISION
+	case "SGHC(I)":
+		return models.COURT_SICC
+	case "SGHC", "SGHCR":
+		if year >= generalDivisionYear {
+			return models.COURT_GENERAL_DIVISION
+		}
+		return models.COURT_HIGH_COURT
+	default:
+		return models.COURT_UNKNOWN
+	}
+}
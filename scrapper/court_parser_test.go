@@ -0,0 +1,39 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"testing"
+)
+
+func TestClassifyCourt(t *testing.T) {
+	tests := []struct {
+		institution   string
+		citation      string
+		court         models.Court
+		citationCourt string
+		mismatch      bool
+	}{
+		{institution: "Court of Appeal", citation: "[2023] SGCA 12", court: models.COURT_OF_APPEAL, citationCourt: "SGCA"},
+		{institution: "Appellate Division of the High Court", citation: "[2022] SGHCA 3", court: models.COURT_APPELLATE_DIVISION, citationCourt: "SGHCA"},
+		{institution: "General Division of the High Court", citation: "[2024] SGHC 112", court: models.COURT_GENERAL_DIVISION, citationCourt: "SGHC"},
+		{institution: "General Division of the High Court", citation: "[2024] SGHCR 7", court: models.COURT_GENERAL_DIVISION, citationCourt: "SGHCR"},
+		{institution: "High Court (Family Division)", citation: "[2019] SGHCF 4", court: models.COURT_FAMILY_DIVISION, citationCourt: "SGHCF"},
+		{institution: "Singapore International Commercial Court", citation: "[2020] SGHC(I) 2", court: models.COURT_SICC, citationCourt: "SGHC(I)"},
+		{institution: "High Court", citation: "[2009] SGHC 75", court: models.COURT_HIGH_COURT, citationCourt: "SGHC"},
+		{institution: "High Court", citation: "[2009] SGCA 75", court: models.COURT_HIGH_COURT, citationCourt: "SGCA", mismatch: true},
+		{institution: "", citation: "[2024] SGHC 1", court: models.COURT_GENERAL_DIVISION, citationCourt: "SGHC"},
+		{institution: "", citation: "[2015] SGHC 1", court: models.COURT_HIGH_COURT, citationCourt: "SGHC"},
+		{institution: "District Court", citation: "", court: models.COURT_UNKNOWN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.institution+" "+tt.citation, func(t *testing.T) {
+			metadata := models.Metadata{JudicalInstitution: tt.institution, CitationNumber: tt.citation}
+			parseCitation(&metadata)
+			classifyCourt(&metadata)
+			if metadata.Court != tt.court || metadata.CitationCourt != tt.citationCourt || metadata.CourtMismatch != tt.mismatch {
+				t.Errorf("court, citation court, mismatch = %q, %q, %v, want %q, %q, %v",
+					metadata.Court, metadata.CitationCourt, metadata.CourtMismatch, tt.court, tt.citationCourt, tt.mismatch)
+			}
+		})
+	}
+}
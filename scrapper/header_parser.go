@@ -0,0 +1,200 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	monthPattern   = `January|February|March|April|May|June|July|August|September|October|November|December`
+	dateListRegex  = regexp.MustCompile(`(\d{1,2}(?:\s*(?:,|and|&|to|–|-)\s*\d{1,2})*)\s+(` + monthPattern + `)(?:,?\s+(\d{4}))?`)
+	dayTokenRegex  = regexp.MustCompile(`\d{1,2}|–|-|to`)
+	reservedRegex  = regexp.MustCompile(`(?i)judge?ment\s+reserved`)
+	partySplitter  = regexp.MustCompile(`\s*(?:…|\.{3,})\s*`)
+	partyNumbering = regexp.MustCompile(`\(\d+\)`)
+	firmRegex      = regexp.MustCompile(`([^()]*)\(([^()]*)\)`)
+	lawyerSplitter = regexp.MustCompile(`\s*,\s*|\s+and\s+|\s*&\s*`)
+	inPersonRegex  = regexp.MustCompile(`(?i)^(?:the\s+)?(.+?)\s+in\s+person$`)
+)
+
+// parseHeaderDates reads the hearing and judgment reserved dates of the header lines following the judges.
+// A line like "12 March 2024; 7 May 2024" ends with the decision date, which is not a hearing date.
+// "Judgment reserved." without a date reserves judgment on the last hearing date.
+func parseHeaderDates(lines []string, decisionDate string) ([]string, []string) {
+	var hearing, reserved []string
+	for _, line := range lines {
+		if reservedRegex.MatchString(line) {
+			dates := parseDates(line)
+			if len(dates) == 0 && len(hearing) > 0 {
+				dates = hearing[len(hearing)-1:]
+			}
+			reserved = append(reserved, dates...)
+			continue
+		}
+
+		segments := strings.Split(line, ";")
+		if len(segments) > 1 {
+			last := parseDates(segments[len(segments)-1])
+			if len(last) == 1 && last[0] == decisionDate {
+				segments = segments[:len(segments)-1]
+			}
+		}
+		hearing = append(hearing, parseDates(strings.Join(segments, ";"))...)
+	}
+	return hearing, reserved
+}
+
+// parseDates returns the dates written in text as RFC3339 strings, in order.
+// Days share the month that follows them, "12, 13 March" and "12–14 March" give several dates,
+// and a date without a year takes the next year in text, as in "21 February, 25 April 2023".
+func parseDates(text string) []string {
+	matches := dateListRegex.FindAllStringSubmatch(text, -1)
+
+	var dates []string
+	for i, match := range matches {
+		year := match[3]
+		for j := i + 1; year == "" && j < len(matches); j++ {
+			year = matches[j][3]
+		}
+		if year == "" {
+			continue
+		}
+
+		for _, day := range parseDays(match[1]) {
+			date, err := time.Parse("2 January 2006", strconv.Itoa(day)+" "+match[2]+" "+year)
+			if err != nil {
+				continue
+			}
+			dates = append(dates, date.Format(time.RFC3339))
+		}
+	}
+	return dates
+}
+
+// parseDays expands a list of days such as "12, 13 and 15" or "12–14".
+func parseDays(text string) []int {
+	var days []int
+	inRange := false
+	for _, token := range dayTokenRegex.FindAllString(text, -1) {
+		day, err := strconv.Atoi(token)
+		if err != nil {
+			inRange = len(days) > 0
+			continue
+		}
+		if inRange {
+			for d := days[len(days)-1] + 1; d < day; d++ {
+				days = append(days, d)
+			}
+			inRange = false
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// parseParties reads the parties section of the header, where names are followed by their role:
+//
+//	Between
+//	Public Prosecutor … Appellant
+//	And
+//	(1) Tan Ah Kow
+//	(2) Lim Ah Seng … Respondents
+//
+// Names without a role are kept with an empty one.
+func parseParties(text string) []models.Party {
+	var parties []models.Party
+	var pending []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		if line == "" || lower == "between" || lower == "and" {
+			continue
+		}
+
+		parts := partySplitter.Split(line, 2)
+		pending = append(pending, partyNames(parts[0])...)
+		if len(parts) == 1 {
+			continue
+		}
+		role := partyRole(parts[1])
+		for _, name := range pending {
+			parties = append(parties, classifyParty(models.Party{Name: name, Role: role}))
+		}
+		pending = nil
+	}
+	for _, name := range pending {
+		parties = append(parties, classifyParty(models.Party{Name: name}))
+	}
+	return parties
+}
+
+// partyNames splits the names numbered "(1) … (2) …" on a line.
+func partyNames(text string) []string {
+	var names []string
+	for _, name := range partyNumbering.Split(text, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// partyRole returns role in lower case and singular, "Respondents" gives "respondent".
+func partyRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if strings.HasSuffix(role, "s") && !strings.HasSuffix(role, "ss") {
+		role = strings.TrimSuffix(role, "s")
+	}
+	return role
+}
+
+// parseCounsel splits a counsel listing such as
+// "Tan Kiat Pheng and Lim Shin Hui (Attorney-General's Chambers) for the appellant; the respondent in person."
+// into the counsel of every party.
+func parseCounsel(text string) []models.Counsel {
+	var counsel []models.Counsel
+	for _, segment := range strings.FieldsFunc(text, func(r rune) bool { return r == ';' || r == '\n' }) {
+		segment = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(segment), "."))
+		segment = strings.TrimSpace(strings.TrimPrefix(segment, "and "))
+		if segment == "" {
+			continue
+		}
+
+		if match := inPersonRegex.FindStringSubmatch(segment); match != nil {
+			counsel = append(counsel, models.Counsel{Party: match[1], InPerson: true})
+			continue
+		}
+
+		lawyers, party := segment, ""
+		if i := strings.LastIndex(strings.ToLower(segment), " for "); i >= 0 {
+			lawyers, party = segment[:i], strings.TrimSpace(segment[i+len(" for "):])
+			party = strings.TrimPrefix(party, "the ")
+		}
+		counsel = append(counsel, models.Counsel{Party: party, Lawyers: parseLawyers(lawyers)})
+	}
+	return counsel
+}
+
+// parseLawyers reads the names of counsel, each group of names followed by their firm in brackets.
+func parseLawyers(text string) []models.Lawyer {
+	var lawyers []models.Lawyer
+	add := func(names string, firm string) {
+		for _, name := range lawyerSplitter.Split(names, -1) {
+			name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "and "))
+			if name != "" {
+				lawyers = append(lawyers, models.Lawyer{Name: name, Firm: strings.TrimSpace(firm)})
+			}
+		}
+	}
+
+	end := 0
+	for _, match := range firmRegex.FindAllStringSubmatchIndex(text, -1) {
+		add(text[match[2]:match[3]], text[match[4]:match[5]])
+		end = match[1]
+	}
+	add(text[end:], "")
+	return lawyers
+}
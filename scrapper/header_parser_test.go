@@ -0,0 +1,132 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseHeaderDates(t *testing.T) {
+	tests := []struct {
+		name         string
+		lines        []string
+		decisionDate string
+		hearing      []string
+		reserved     []string
+	}{
+		{
+			name:         "hearing and decision",
+			lines:        []string{"12 March 2024; 7 May 2024"},
+			decisionDate: "2024-05-07T00:00:00Z",
+			hearing:      []string{"2024-03-12T00:00:00Z"},
+		},
+		{
+			name:         "ex tempore",
+			lines:        []string{"7 May 2024"},
+			decisionDate: "2024-05-07T00:00:00Z",
+			hearing:      []string{"2024-05-07T00:00:00Z"},
+		},
+		{
+			name:     "lists, ranges and shared year",
+			lines:    []string{"21 February, 3–5 April 2023", "Judgment reserved."},
+			hearing:  []string{"2023-02-21T00:00:00Z", "2023-04-03T00:00:00Z", "2023-04-04T00:00:00Z", "2023-04-05T00:00:00Z"},
+			reserved: []string{"2023-04-05T00:00:00Z"},
+		},
+		{
+			name:     "dated reservation",
+			lines:    []string{"10 and 11 January 2022", "Judgment reserved on 20 January 2022"},
+			hearing:  []string{"2022-01-10T00:00:00Z", "2022-01-11T00:00:00Z"},
+			reserved: []string{"2022-01-20T00:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hearing, reserved := parseHeaderDates(tt.lines, tt.decisionDate)
+			if !reflect.DeepEqual(hearing, tt.hearing) {
+				t.Errorf("hearing = %v, want %v", hearing, tt.hearing)
+			}
+			if !reflect.DeepEqual(reserved, tt.reserved) {
+				t.Errorf("reserved = %v, want %v", reserved, tt.reserved)
+			}
+		})
+	}
+}
+
+func TestParseParties(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []models.Party
+	}{
+		{
+			name: "one each",
+			text: "Between\nPublic Prosecutor … Appellant\nAnd\nTan Ah Kow … Respondent",
+			want: []models.Party{
+				{Name: "Public Prosecutor", Role: "appellant", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+				{Name: "Tan Ah Kow", Role: "respondent", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+			},
+		},
+		{
+			name: "numbered on their own lines",
+			text: "Between\n(1) Tan Ah Kow\n(2) Lim Ah Seng\n... Appellants\nAnd\nPublic Prosecutor\n... Respondent",
+			want: []models.Party{
+				{Name: "Tan Ah Kow", Role: "appellant", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+				{Name: "Lim Ah Seng", Role: "appellant", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+				{Name: "Public Prosecutor", Role: "respondent", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+			},
+		},
+		{
+			name: "without roles",
+			text: "Tan Ah Kow",
+			want: []models.Party{{Name: "Tan Ah Kow", EntityType: models.PARTY_ENTITY_INDIVIDUAL}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseParties(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseParties() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCounsel(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []models.Counsel
+	}{
+		{
+			name: "in person",
+			text: "Tan Kiat Pheng (Deputy Public Prosecutor) for the appellant; the respondent in person.",
+			want: []models.Counsel{
+				{Party: "appellant", Lawyers: []models.Lawyer{{Name: "Tan Kiat Pheng", Firm: "Deputy Public Prosecutor"}}},
+				{Party: "respondent", InPerson: true},
+			},
+		},
+		{
+			name: "several firms",
+			text: "Davinder Singh SC, Jaswant Singh (Davinder Singh Chambers LLC) and Ang Cheng Hock SC (Allen & Gledhill LLP) for the first and second respondents.",
+			want: []models.Counsel{{
+				Party: "first and second respondents",
+				Lawyers: []models.Lawyer{
+					{Name: "Davinder Singh SC", Firm: "Davinder Singh Chambers LLC"},
+					{Name: "Jaswant Singh", Firm: "Davinder Singh Chambers LLC"},
+					{Name: "Ang Cheng Hock SC", Firm: "Allen & Gledhill LLP"},
+				},
+			}},
+		},
+		{
+			name: "without party",
+			text: "Lim Shin Hui",
+			want: []models.Counsel{{Lawyers: []models.Lawyer{{Name: "Lim Shin Hui"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCounsel(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCounsel() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package scrapper
+
+import (
+	"errors"
+	"fmt"
+	"lexicon/singapore-supreme-court-crawler/casenumber"
+	"lexicon/singapore-supreme-court-crawler/citation"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"regexp"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	mdp "github.com/JohannesKaufmann/html-to-markdown/plugin"
+	gq "github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog/log"
+)
+
+// parseJudgement fills the metadata of extraction from the HTML of #divJudgement.
+// It works on the stored SiteContent as well as on a freshly scraped page.
+func parseJudgement(siteContent string, extraction *repository.Extraction, urlFrontier *repository.UrlFrontier) error {
+	doc, err := document.Parse(siteContent)
+	if err != nil {
+		return err
+	}
+
+	judgement, err := doc.Find("#divJudgement")
+	if err != nil {
+		return fmt.Errorf("failed to find judgement: %w", err)
+	}
+
+	content, err := judgement.Find("content")
+	switch {
+	case errors.Is(err, document.ErrNotFound):
+		err = parseOldTemplate(judgement, extraction, urlFrontier)
+	case err == nil:
+		err = parseNewTemplate(content, extraction, urlFrontier)
+	}
+	if err != nil {
+		return err
+	}
+
+	parseCitation(&extraction.Metadata)
+	classifyCourt(&extraction.Metadata)
+	if extraction.Metadata.CourtMismatch {
+		log.Warn().Msgf("Court %s of %s does not match its citation %s", extraction.Metadata.Court, urlFrontier.Url, extraction.Metadata.CitationNumber)
+	}
+	return nil
+}
+
+// parseCitation sets the structured citation of metadata and makes its CitationNumber canonical.
+// A citation number that cannot be parsed is kept as written, without a structured citation.
+func parseCitation(metadata *models.Metadata) {
+	parsed, err := citation.Parse(metadata.CitationNumber)
+	if err != nil {
+		metadata.Citation = nil
+		if metadata.CitationNumber != "" {
+			log.Warn().Err(err).Msg("Keeping citation number as written")
+		}
+		return
+	}
+	metadata.CitationNumber, metadata.Citation = parsed.String(), &parsed
+}
+
+var caseNumberSplitter = regexp.MustCompile(`\s*[,;]\s*`)
+
+// parseCaseNumbers parses the case numbers of a judgement, those it does not recognize are kept as written.
+func parseCaseNumbers(numbers []string) []casenumber.CaseNumber {
+	parsed := []casenumber.CaseNumber{}
+	for _, number := range numbers {
+		if strings.TrimSpace(number) == "" {
+			continue
+		}
+		n, err := casenumber.Parse(number)
+		if err != nil {
+			log.Warn().Err(err).Msg("Keeping case number as written")
+		}
+		parsed = append(parsed, n)
+	}
+	return parsed
+}
+
+// findText returns the text of the first descendant of e matching selector.
+func findText(e document.Node, selector string) (string, error) {
+	found, err := e.Find(selector)
+	if err != nil {
+		return "", err
+	}
+	return found.Text()
+}
+
+// convertVerdict returns the text and the markdown of the judgement paragraphs.
+// A paragraph that cannot be converted is logged and left out of the markdown.
+func convertVerdict(paragraphs []document.Node) ([]string, []string) {
+	converter := newVerdictConverter()
+
+	var rawVerdict []string
+	var markdownVerdict []string
+	for _, paragraph := range paragraphs {
+		text, err := paragraph.Text()
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting text")
+			continue
+		}
+		rawVerdict = append(rawVerdict, text)
+
+		html, err := paragraph.HTML()
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting html")
+			continue
+		}
+		md, err := converter.ConvertString(html)
+		if err != nil {
+			log.Error().Err(err).Msg("Error converting verdict")
+			continue
+		}
+
+		markdownVerdict = append(markdownVerdict, md)
+	}
+
+	return rawVerdict, markdownVerdict
+}
+
+// newVerdictConverter converts the judgement paragraphs of both templates to markdown.
+func newVerdictConverter() *md.Converter {
+	converter := md.NewConverter("", true, nil)
+	converter.Use(mdp.GitHubFlavored())
+
+	prefixRule := func(prefix string, filters ...string) md.Rule {
+		return md.Rule{
+			Filter: filters,
+			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
+				content = strings.TrimSpace(content)
+				return md.String(prefix + content)
+			},
+		}
+	}
+
+	converter.AddRules(
+		prefixRule("** ", "p.Judg-Author"),
+		prefixRule("# ", "p.Judg-Heading-1"),
+		prefixRule("## ", "p.Judg-Heading-2"),
+		prefixRule("", "p.Judg-1", "p.Judg-2", "p.Judg-List-1-Item", "p.Judg-List-2-Item"),
+		prefixRule("> ", "p.Judge-Quote-1"),
+		prefixRule(">> ", "p.Judge-Quote-2"),
+		prefixRule("- ", "p.Judg-QuoteList-2"),
+		prefixRule("  - ", "p.Judg-QuoteList-3"),
+		prefixRule("^", "p.Footnote"),
+		prefixRule("1. ", "p.Judg-List-1-No"),
+		prefixRule("a. ", "p.Judg-List-2-No"),
+	)
+
+	return converter
+}
@@ -0,0 +1,73 @@
+package scrapper
+
+import (
+	"context"
+	"lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/document"
+	"lexicon/singapore-supreme-court-crawler/document/documenttest"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"testing"
+)
+
+func TestParseJudgement(t *testing.T) {
+	server := documenttest.NewServer(t, "testdata")
+	c := &ScrapperImpl{HttpFetcher: document.NewHttpFetcher(server.Client())}
+
+	tests := []struct {
+		name        string
+		urlFrontier repository.UrlFrontier
+	}{
+		{
+			name: "judgement_new_template",
+			urlFrontier: repository.UrlFrontier{
+				Url: "https://www.elitigation.sg/gd/s/2024_SGHC_112",
+				Metadata: models.UrlFrontierMetadata{
+					CitationNumber: "[2024] SGHC 112",
+					DecisionDate:   "2024-05-07T00:00:00Z",
+					Title:          "Public Prosecutor v Tan Ah Kow",
+					Categories:     []string{"Criminal Law — Statutory offences — Prevention of Corruption Act"},
+					CaseNumbers:    []string{"Magistrate's Appeal No 9112 of 2023"},
+				},
+			},
+		},
+		{
+			name: "judgement_old_template",
+			urlFrontier: repository.UrlFrontier{
+				Url: "https://www.elitigation.sg/gd/s/2009_SGHC_75",
+				Metadata: models.UrlFrontierMetadata{
+					CitationNumber: "[2009] SGHC 75",
+					DecisionDate:   "2009-03-30T00:00:00Z",
+					Title:          "Public Prosecutor v Chew Kim Seng",
+					Categories:     []string{},
+					CaseNumbers:    []string{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documenttest.Record(t, "testdata", tt.name+".html", tt.urlFrontier.Url)
+			siteContent, pdfUrl, err := c.fetchJudgement(context.Background(), server.URL+"/"+tt.name+".html")
+			if err != nil {
+				t.Fatalf("fetchJudgement() error = %v", err)
+			}
+
+			var extraction repository.Extraction
+			extraction.Metadata.PdfUrl = pdfUrl
+			if err := parseJudgement(siteContent, &extraction, &tt.urlFrontier); err != nil {
+				t.Fatalf("parseJudgement() error = %v", err)
+			}
+
+			documenttest.AssertGolden(t, "testdata/"+tt.name+".golden.json", extraction.Metadata)
+		})
+	}
+}
+
+func TestParseJudgementWithoutJudgement(t *testing.T) {
+	var extraction repository.Extraction
+	err := parseJudgement("<html><body><p>Not found</p></body></html>", &extraction, &repository.UrlFrontier{})
+	if err == nil {
+		t.Fatal("parseJudgement() error = nil, want an error for a page without #divJudgement")
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "slices"
+
+// Court is a court of the Supreme Court of Singapore, as classified from the judicial institution of a judgement.
+type Court string
+
+const (
+	COURT_UNKNOWN    Court = ""
+	COURT_OF_APPEAL  Court = "court_of_appeal"
+	COURT_HIGH_COURT Court = "high_court"
+	// The divisions of the High Court, since 2 January 2021 for the appellate and general ones
+	COURT_APPELLATE_DIVISION Court = "appellate_division"
+	COURT_GENERAL_DIVISION   Court = "general_division"
+	COURT_FAMILY_DIVISION    Court = "family_division"
+	// Singapore International Commercial Court, a division of the High Court
+	COURT_SICC Court = "sicc"
+)
+
+// courtCitationCodes are the neutral citation codes of the judgements of each court.
+// SGHCR judgements are decisions of the registrars of the High Court.
+var courtCitationCodes = map[Court][]string{
+	COURT_OF_APPEAL:          {"SGCA", "SGCA(I)"},
+	COURT_HIGH_COURT:         {"SGHC", "SGHCR"},
+	COURT_APPELLATE_DIVISION: {"SGHCA"},
+	COURT_GENERAL_DIVISION:   {"SGHC", "SGHCR"},
+	COURT_FAMILY_DIVISION:    {"SGHCF"},
+	COURT_SICC:               {"SGHC(I)", "SGHC"},
+}
+
+// Parent returns the court c is a division of, COURT_UNKNOWN for the top of the hierarchy.
+func (c Court) Parent() Court {
+	switch c {
+	case COURT_APPELLATE_DIVISION, COURT_GENERAL_DIVISION, COURT_FAMILY_DIVISION, COURT_SICC:
+		return COURT_HIGH_COURT
+	default:
+		return COURT_UNKNOWN
+	}
+}
+
+// AcceptsCitationCode reports whether judgements of c are cited with code, such as "SGHC".
+func (c Court) AcceptsCitationCode(code string) bool {
+	return slices.Contains(courtCitationCodes[c], code)
+}
@@ -1,19 +1,98 @@
 package models
 
+import (
+	"lexicon/singapore-supreme-court-crawler/casenumber"
+	"lexicon/singapore-supreme-court-crawler/citation"
+	"reflect"
+	"strings"
+)
+
 var EmptyMetadata Metadata
 
 type Metadata struct {
-	Title              string   `json:"title"`
-	Defendant          string   `json:"defendant"`
-	Numbers            []string `json:"numbers"`
-	CitationNumber     string   `json:"citation_number"`
-	Classifications    []string `json:"classifications"`
-	Year               string   `json:"year"`
-	JudicalInstitution string   `json:"judicial_institution"`
-	Judges             string   `json:"judges"`
-	Counsel            string   `json:"counsel"`
-	Verdict            string   `json:"verdict"`
-	VerdictMarkdown    string   `json:"verdict_markdown"`
-	DecisionDate       string   `json:"decision_date"`
-	PdfUrl             string   `json:"pdf_url"`
+	Title          string                  `json:"title"`
+	Defendant      string                  `json:"defendant"`
+	Numbers        []casenumber.CaseNumber `json:"numbers"`
+	CitationNumber string                  `json:"citation_number"`
+	// Citation is CitationNumber parsed, nil when it is not a valid neutral citation
+	Citation           *citation.Citation `json:"citation"`
+	Classifications    []string           `json:"classifications"`
+	Year               string             `json:"year"`
+	JudicalInstitution string             `json:"judicial_institution"`
+	// Court is JudicalInstitution classified, CitationCourt the court code of CitationNumber.
+	// CourtMismatch flags a citation code that is not one of Court
+	Court         Court  `json:"court"`
+	CitationCourt string `json:"citation_court"`
+	CourtMismatch bool   `json:"court_mismatch"`
+	Judges        string `json:"judges"`
+	// Coram is Judges split per judge
+	Coram   []Judge `json:"coram"`
+	Counsel string  `json:"counsel"`
+	// CounselByParty is Counsel split per represented party
+	CounselByParty []Counsel `json:"counsel_by_party"`
+	Parties        []Party   `json:"parties"`
+	// HearingDates and ReservedDates are RFC3339 dates, like DecisionDate
+	HearingDates    []string `json:"hearing_dates"`
+	ReservedDates   []string `json:"reserved_dates"`
+	Verdict         string   `json:"verdict"`
+	VerdictMarkdown string   `json:"verdict_markdown"`
+	DecisionDate    string   `json:"decision_date"`
+	PdfUrl          string   `json:"pdf_url"`
+}
+
+// Judge is a member of the coram, with the honorific written after the name, such as "JA", and the office it stands for.
+// ID is the canonical name of the judge in the judges registry, the same across spellings and templates.
+type Judge struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Honorific string `json:"honorific"`
+	Role      string `json:"role"`
+}
+
+const (
+	PARTY_ENTITY_INDIVIDUAL = "individual"
+	PARTY_ENTITY_COMPANY    = "company"
+	PARTY_ENTITY_GOVERNMENT = "government"
+)
+
+// Party is a party to the case with its side, lower case and singular, such as "appellant", "respondent", "applicant" or "accused".
+// EntityType is one of the PARTY_ENTITY constants and Prosecution is set for the Public Prosecutor.
+type Party struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	EntityType  string `json:"entity_type"`
+	Prosecution bool   `json:"prosecution"`
+}
+
+// Counsel lists the lawyers of the party they appear for, as written after "for the".
+// InPerson is set for a party acting for itself.
+type Counsel struct {
+	Party    string   `json:"party"`
+	Lawyers  []Lawyer `json:"lawyers"`
+	InPerson bool     `json:"in_person"`
+}
+
+// Lawyer is a counsel with the firm or office given in brackets after the name.
+type Lawyer struct {
+	Name string `json:"name"`
+	Firm string `json:"firm"`
+}
+
+// ChangedFields returns the json names of the fields that differ between m and other.
+// Nil and empty lists are considered equal.
+func (m Metadata) ChangedFields(other Metadata) []string {
+	changed := []string{}
+	a, b := reflect.ValueOf(m), reflect.ValueOf(other)
+	for i := 0; i < a.NumField(); i++ {
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Slice && fa.Len() == 0 && fb.Len() == 0 {
+			continue
+		}
+		if reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+		changed = append(changed, name)
+	}
+	return changed
 }
@@ -0,0 +1,7 @@
+package models
+
+// ParagraphPage locates a numbered paragraph of a judgement in its PDF.
+type ParagraphPage struct {
+	Paragraph int `json:"paragraph"`
+	Page      int `json:"page"`
+}
@@ -0,0 +1,136 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/casenumber"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	titleSidesRegex     = regexp.MustCompile(`\s+(?:v|vs)\.?\s+`)
+	partiesRowSides     = regexp.MustCompile(`\s*—\s*`)
+	otherMattersRegex   = regexp.MustCompile(`(?i)\s+and\s+(?:an)?others?\s+(?:matters?|appeals?|applications?|suits?|summons(?:es)?|motions?|references?|cases?)$`)
+	unnamedPartiesRegex = regexp.MustCompile(`(?i)\s+and\s+(?:another|others|\d+\s+others)$`)
+	reRegex             = regexp.MustCompile(`(?i)^re\s+|,\s*re$`)
+	prosecutionRegex    = regexp.MustCompile(`(?i)^public\s+prosecutor$`)
+	companyRegex        = regexp.MustCompile(`(?i)\b(?:pte\.?|ltd\.?|limited|llp|llc|inc\.?|incorporated|corporation|corp\.?|bhd|plc|company|co\.|gmbh|holdings|bank|trust|fund)(?:\s|$|\))`)
+	governmentRegex     = regexp.MustCompile(`(?i)public\s+prosecutor|attorney-general|comptroller|ministry|minister|government|authority|commissioner|registrar|council|board|official\s+assignee|republic\s+of\s+singapore`)
+)
+
+// caseRoles gives the roles of the first and second sides of a title for each case type.
+var caseRoles = map[string][2]string{
+	"CA":  {"appellant", "respondent"},
+	"CCA": {"appellant", "respondent"},
+	"MA":  {"appellant", "respondent"},
+	"DCA": {"appellant", "respondent"},
+	"RA":  {"appellant", "respondent"},
+	"CM":  {"applicant", "respondent"},
+	"CRF": {"applicant", "respondent"},
+	"CR":  {"applicant", "respondent"},
+	"OS":  {"applicant", "respondent"},
+	"OA":  {"applicant", "respondent"},
+	"SUM": {"applicant", "respondent"},
+	"CC":  {"prosecution", "accused"},
+	"S":   {"plaintiff", "defendant"},
+	"OC":  {"claimant", "defendant"},
+}
+
+// parseTitleParties reads the parties of a title such as "Tan Ah Kow and another v Public Prosecutor and another matter".
+func parseTitleParties(title string, numbers []casenumber.CaseNumber) []models.Party {
+	title = strings.TrimSpace(otherMattersRegex.ReplaceAllString(strings.TrimSpace(title), ""))
+	return sidesParties(titleSidesRegex.Split(title, 2), numbers)
+}
+
+// parsePartiesRow reads the "Parties" row of the old template, "Public Prosecutor — Chew Kim Seng".
+func parsePartiesRow(row string, numbers []casenumber.CaseNumber) []models.Party {
+	return sidesParties(partiesRowSides.Split(strings.TrimSpace(row), 2), numbers)
+}
+
+// sidesParties returns the parties of the two sides of a case, several parties of a side being separated by ";".
+// Roles follow the type of the first recognized case number: the first side of an appeal is the appellant,
+// whether it is the prosecution or the accused. Without a known type only a prosecution of an accused is told apart.
+func sidesParties(sides []string, numbers []casenumber.CaseNumber) []models.Party {
+	if len(sides) == 1 {
+		sides[0] = reRegex.ReplaceAllString(sides[0], "")
+	}
+
+	sideParties := make([][]models.Party, len(sides))
+	for i, side := range sides {
+		side = unnamedPartiesRegex.ReplaceAllString(strings.TrimSpace(side), "")
+		for _, name := range strings.Split(side, ";") {
+			if name = strings.TrimSpace(name); name != "" {
+				sideParties[i] = append(sideParties[i], classifyParty(models.Party{Name: name}))
+			}
+		}
+	}
+
+	if roles, ok := rolesOf(numbers, sideParties[0]); ok && len(sides) == 2 {
+		for i := range sideParties {
+			for j := range sideParties[i] {
+				sideParties[i][j].Role = roles[i]
+			}
+		}
+	}
+	return slices.Concat(sideParties...)
+}
+
+// rolesOf returns the roles of the sides for the first case number of a known type.
+// Without one, a case opened by the prosecution has the prosecution and the accused as sides.
+func rolesOf(numbers []casenumber.CaseNumber, firstSide []models.Party) ([2]string, bool) {
+	for _, number := range numbers {
+		if roles, ok := caseRoles[number.Type]; ok {
+			return roles, true
+		}
+	}
+	if len(firstSide) > 0 && firstSide[0].Prosecution {
+		return caseRoles["CC"], true
+	}
+	return [2]string{}, false
+}
+
+// classifyParty sets the entity type and prosecution flag of party from its name.
+func classifyParty(party models.Party) models.Party {
+	party.Prosecution = prosecutionRegex.MatchString(party.Name)
+	switch {
+	case governmentRegex.MatchString(party.Name):
+		party.EntityType = models.PARTY_ENTITY_GOVERNMENT
+	case companyRegex.MatchString(party.Name):
+		party.EntityType = models.PARTY_ENTITY_COMPANY
+	default:
+		party.EntityType = models.PARTY_ENTITY_INDIVIDUAL
+	}
+	return party
+}
+
+// defendantOf returns the names of the parties facing the prosecution, or those of the second side of a civil case,
+// "accused", "respondent" and "defendant" parties in that order.
+func defendantOf(parties []models.Party) string {
+	prosecuted := false
+	for _, party := range parties {
+		prosecuted = prosecuted || party.Prosecution
+	}
+
+	var names []string
+	if prosecuted {
+		for _, party := range parties {
+			if !party.Prosecution && party.EntityType != models.PARTY_ENTITY_GOVERNMENT {
+				names = append(names, party.Name)
+			}
+		}
+		return strings.Join(names, "; ")
+	}
+
+	for _, role := range []string{"accused", "respondent", "defendant"} {
+		for _, party := range parties {
+			if party.Role == role {
+				names = append(names, party.Name)
+			}
+		}
+		if len(names) > 0 {
+			break
+		}
+	}
+	return strings.Join(names, "; ")
+}
@@ -0,0 +1,90 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/casenumber"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseTitleParties(t *testing.T) {
+	ma, _ := casenumber.Parse("Magistrate's Appeal No 9112 of 2023")
+	suit, _ := casenumber.Parse("HC/S 123/2019")
+
+	tests := []struct {
+		name      string
+		title     string
+		numbers   []casenumber.CaseNumber
+		want      []models.Party
+		defendant string
+	}{
+		{
+			name:    "prosecution appeal",
+			title:   "Public Prosecutor v Tan Ah Kow",
+			numbers: []casenumber.CaseNumber{ma},
+			want: []models.Party{
+				{Name: "Public Prosecutor", Role: "appellant", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+				{Name: "Tan Ah Kow", Role: "respondent", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+			},
+			defendant: "Tan Ah Kow",
+		},
+		{
+			name:    "reversed appeal with other matters",
+			title:   "Tan Ah Kow and another v Public Prosecutor and another matter",
+			numbers: []casenumber.CaseNumber{ma},
+			want: []models.Party{
+				{Name: "Tan Ah Kow", Role: "appellant", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+				{Name: "Public Prosecutor", Role: "respondent", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+			},
+			defendant: "Tan Ah Kow",
+		},
+		{
+			name:  "criminal case without numbers",
+			title: "Public Prosecutor v Tan Ah Kow; Lim Ah Seng",
+			want: []models.Party{
+				{Name: "Public Prosecutor", Role: "prosecution", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+				{Name: "Tan Ah Kow", Role: "accused", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+				{Name: "Lim Ah Seng", Role: "accused", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+			},
+			defendant: "Tan Ah Kow; Lim Ah Seng",
+		},
+		{
+			name:    "civil suit",
+			title:   "Lim Ah Seng v ABC Pte Ltd",
+			numbers: []casenumber.CaseNumber{suit},
+			want: []models.Party{
+				{Name: "Lim Ah Seng", Role: "plaintiff", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+				{Name: "ABC Pte Ltd", Role: "defendant", EntityType: models.PARTY_ENTITY_COMPANY},
+			},
+			defendant: "ABC Pte Ltd",
+		},
+		{
+			name:      "re",
+			title:     "Re Tan Ah Kow",
+			want:      []models.Party{{Name: "Tan Ah Kow", EntityType: models.PARTY_ENTITY_INDIVIDUAL}},
+			defendant: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTitleParties(tt.title, tt.numbers)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTitleParties() = %+v, want %+v", got, tt.want)
+			}
+			if defendant := defendantOf(got); defendant != tt.defendant {
+				t.Errorf("defendantOf() = %q, want %q", defendant, tt.defendant)
+			}
+		})
+	}
+}
+
+func TestParsePartiesRow(t *testing.T) {
+	got := parsePartiesRow("Public Prosecutor — Chew Kim Seng", nil)
+	want := []models.Party{
+		{Name: "Public Prosecutor", Role: "prosecution", EntityType: models.PARTY_ENTITY_GOVERNMENT, Prosecution: true},
+		{Name: "Chew Kim Seng", Role: "accused", EntityType: models.PARTY_ENTITY_INDIVIDUAL},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePartiesRow() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+package scrapper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// pdfText is the text layer of a judgement PDF.
+type pdfText struct {
+	// Text holds the lines of every page, pages are separated by a form feed
+	Text           string
+	PageCount      int
+	ParagraphPages []models.ParagraphPage
+}
+
+// TJ offsets are in thousandths of the font size, a gap wider than this separates two words
+const pdfWordGap = -200
+
+var (
+	paragraphNumberRegex = regexp.MustCompile(`^(\d{1,4})\s+(\S+)`)
+	months               = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+)
+
+// readPdfText reads the PDF stored at link back from the artifact store and extracts its text.
+func readPdfText(ctx context.Context, link string) (pdfText, error) {
+	if common.Artifacts == nil {
+		return pdfText{}, errors.New("artifact store is not set")
+	}
+
+	r, err := common.Artifacts.Open(ctx, link)
+	if err != nil {
+		return pdfText{}, err
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return pdfText{}, err
+	}
+	return parsePdf(content)
+}
+
+// downloadedPdfText extracts the text of the PDF downloaded by the scrape.
+// Only a PDF kept unchanged on a 304 Not Modified response is read back from the artifact store.
+func downloadedPdfText(ctx context.Context, artifact services.Artifact) (pdfText, error) {
+	if artifact.Content == nil {
+		return readPdfText(ctx, artifact.Link)
+	}
+	return parsePdf(artifact.Content)
+}
+
+// parsePdf extracts the lines of text of every page of the PDF in content, and the page of each numbered paragraph.
+// Scanned PDFs without a text layer give an empty text.
+func parsePdf(content []byte) (text pdfText, err error) {
+	// The pdf package panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = pdfText{}, retry.Parse(fmt.Errorf("malformed pdf: %v", r))
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return pdfText{}, retry.Parse(fmt.Errorf("failed to open pdf: %w", err))
+	}
+
+	text.PageCount = reader.NumPage()
+	pages := make([]string, 0, text.PageCount)
+	lastParagraph := 0
+	for i := 1; i <= text.PageCount; i++ {
+		lines := pageLines(reader.Page(i))
+		for _, line := range lines {
+			if paragraph, ok := paragraphNumber(line, lastParagraph); ok {
+				text.ParagraphPages = append(text.ParagraphPages, models.ParagraphPage{Paragraph: paragraph, Page: i})
+				lastParagraph = paragraph
+			}
+		}
+		pages = append(pages, strings.Join(lines, "\n"))
+	}
+	text.Text = strings.Join(pages, "\f")
+
+	return text, nil
+}
+
+// pageLines returns the lines of text shown on page, in the order of its content stream.
+// A line ends when the text position moves vertically.
+func pageLines(page pdf.Page) []string {
+	if page.V.IsNull() {
+		return nil
+	}
+
+	encodings := map[string]pdf.TextEncoding{}
+	for _, name := range page.Fonts() {
+		encodings[name] = page.Font(name).Encoder()
+	}
+
+	var (
+		lines []string
+		line  strings.Builder
+		y     float64
+		enc   pdf.TextEncoding
+	)
+	endLine := func() {
+		if s := strings.Join(strings.Fields(line.String()), " "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+	moveTo := func(newY float64) {
+		if math.Abs(newY-y) > 1 {
+			endLine()
+		} else {
+			line.WriteByte(' ')
+		}
+		y = newY
+	}
+	show := func(raw string) {
+		if enc == nil {
+			line.WriteString(raw)
+			return
+		}
+		line.WriteString(enc.Decode(raw))
+	}
+
+	interpret := func(stream pdf.Value) {
+		pdf.Interpret(stream, func(stk *pdf.Stack, op string) {
+			args := make([]pdf.Value, stk.Len())
+			for i := len(args) - 1; i >= 0; i-- {
+				args[i] = stk.Pop()
+			}
+
+			switch op {
+			case "BT":
+				// The text line matrix starts again from the origin
+				y = 0
+			case "Tf":
+				if len(args) == 2 {
+					enc = encodings[args[0].Name()]
+				}
+			case "Td", "TD":
+				if len(args) == 2 {
+					moveTo(y + args[1].Float64())
+				}
+			case "Tm":
+				if len(args) == 6 {
+					moveTo(args[5].Float64())
+				}
+			case "T*":
+				endLine()
+			case "'", "\"":
+				endLine()
+				if len(args) > 0 {
+					show(args[len(args)-1].RawString())
+				}
+			case "Tj":
+				if len(args) == 1 {
+					show(args[0].RawString())
+				}
+			case "TJ":
+				if len(args) != 1 {
+					return
+				}
+				for i := 0; i < args[0].Len(); i++ {
+					v := args[0].Index(i)
+					switch v.Kind() {
+					case pdf.String:
+						show(v.RawString())
+					case pdf.Integer, pdf.Real:
+						if v.Float64() < pdfWordGap {
+							line.WriteByte(' ')
+						}
+					}
+				}
+			}
+		})
+	}
+
+	contents := page.V.Key("Contents")
+	if contents.Kind() == pdf.Array {
+		for i := 0; i < contents.Len(); i++ {
+			interpret(contents.Index(i))
+		}
+	} else {
+		interpret(contents)
+	}
+	endLine()
+
+	return lines
+}
+
+// paragraphNumber returns the number of the paragraph starting on line, which must follow lastParagraph.
+// Requiring the next number keeps page numbers, footnotes and dates such as "2 March 2024" out.
+func paragraphNumber(line string, lastParagraph int) (int, bool) {
+	match := paragraphNumberRegex.FindStringSubmatch(line)
+	if match == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(match[1])
+	if err != nil || number != lastParagraph+1 {
+		return 0, false
+	}
+	for _, month := range months {
+		if strings.HasPrefix(match[2], month) {
+			return 0, false
+		}
+	}
+	return number, true
+}
+
+// setPdfText stores text on extraction.
+func setPdfText(extraction *repository.Extraction, text pdfText) {
+	pageCount := int32(text.PageCount)
+	extraction.PdfText = &text.Text
+	extraction.PdfPageCount = &pageCount
+	extraction.PdfParagraphPages = text.ParagraphPages
+}
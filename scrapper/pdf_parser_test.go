@@ -0,0 +1,28 @@
+package scrapper
+
+import (
+	"lexicon/singapore-supreme-court-crawler/document/documenttest"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"os"
+	"testing"
+)
+
+func TestParsePdf(t *testing.T) {
+	content, err := os.ReadFile("testdata/judgement.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := parsePdf(content)
+	if err != nil {
+		t.Fatalf("parsePdf() error = %v", err)
+	}
+	documenttest.AssertGolden(t, "testdata/judgement.pdf.golden.json", text)
+}
+
+func TestParsePdfMalformed(t *testing.T) {
+	_, err := parsePdf([]byte("%PDF-1.4\nnot a pdf"))
+	if err == nil || retry.Classify(err) != retry.ERROR_CLASS_PARSE {
+		t.Fatalf("parsePdf() error = %v, want a parse error", err)
+	}
+}
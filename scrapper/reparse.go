@@ -0,0 +1,151 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// ReparseOptions selects the extractions rebuilt by ReparseAll.
+type ReparseOptions struct {
+	// Since only reparses extractions updated at or after it
+	Since     time.Time
+	BatchSize int32
+	// DryRun reports the changes without storing them
+	DryRun bool
+	// PdfText also extracts the text of the stored PDFs, which needs the artifact store
+	PdfText bool
+}
+
+// ReparseReport counts the reparsed extractions and how often each metadata field changed.
+type ReparseReport struct {
+	Reparsed int            `json:"reparsed"`
+	Changed  int            `json:"changed"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Fields   map[string]int `json:"fields"`
+	// PdfTexts counts the extractions whose PDF text was extracted
+	PdfTexts int `json:"pdf_texts"`
+}
+
+// ReparseAll rebuilds the metadata of the stored extractions from their SiteContent, without network access.
+// Extractions without SiteContent are skipped and those that fail to parse keep their metadata.
+// With opts.PdfText the text of their stored PDF is extracted again as well.
+func ReparseAll(ctx context.Context, opts ReparseOptions) (ReparseReport, error) {
+	report := ReparseReport{Fields: map[string]int{}}
+	afterId := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		extractions, err := services.ListExtractions(ctx, opts.Since, afterId, opts.BatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(extractions) == 0 {
+			return report, nil
+		}
+		afterId = extractions[len(extractions)-1].ID
+
+		urlFrontiers, err := crawler_service.GetUrlFrontiersByIds(ctx, lo.Map(extractions, func(e repository.Extraction, _ int) string {
+			return e.UrlFrontierID
+		}))
+		if err != nil {
+			return report, err
+		}
+		urlFrontierById := lo.KeyBy(urlFrontiers, func(u repository.UrlFrontier) string {
+			return u.ID
+		})
+
+		if opts.PdfText {
+			if err := reparsePdfTexts(ctx, extractions, opts.DryRun, &report); err != nil {
+				return report, err
+			}
+		}
+
+		changed := []repository.Extraction{}
+		for _, extraction := range extractions {
+			urlFrontier, ok := urlFrontierById[extraction.UrlFrontierID]
+			if !ok || extraction.SiteContent == nil {
+				report.Skipped++
+				continue
+			}
+
+			metadata, err := reparseMetadata(extraction, urlFrontier)
+			if err != nil {
+				log.Error().Err(err).Msgf("Error reparsing extraction %s", extraction.ID)
+				report.Failed++
+				continue
+			}
+			report.Reparsed++
+
+			fields := extraction.Metadata.ChangedFields(metadata)
+			if len(fields) == 0 {
+				continue
+			}
+			log.Info().Strs("fields", fields).Msgf("Extraction %s changed", extraction.ID)
+			report.Changed++
+			for _, field := range fields {
+				report.Fields[field]++
+			}
+
+			extraction.Metadata = metadata
+			changed = append(changed, extraction)
+		}
+
+		if opts.DryRun || len(changed) == 0 {
+			continue
+		}
+		if err := services.UpdateExtractionMetadata(ctx, changed); err != nil {
+			return report, fmt.Errorf("failed to store reparsed metadata: %w", err)
+		}
+	}
+}
+
+// reparsePdfTexts extracts the text of the stored PDF of extractions and stores it unless dryRun.
+// Extractions without a PDF are left out and those that fail to parse keep their text.
+func reparsePdfTexts(ctx context.Context, extractions []repository.Extraction, dryRun bool, report *ReparseReport) error {
+	extracted := []repository.Extraction{}
+	for _, extraction := range extractions {
+		if extraction.ArtifactLink == nil {
+			continue
+		}
+		text, err := readPdfText(ctx, *extraction.ArtifactLink)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error extracting pdf text of extraction %s", extraction.ID)
+			report.Failed++
+			continue
+		}
+		report.PdfTexts++
+		setPdfText(&extraction, text)
+		extracted = append(extracted, extraction)
+	}
+
+	if dryRun || len(extracted) == 0 {
+		return nil
+	}
+	if err := services.UpdateExtractionPdfText(ctx, extracted); err != nil {
+		return fmt.Errorf("failed to store pdf text: %w", err)
+	}
+	return nil
+}
+
+// reparseMetadata parses the stored SiteContent of extraction again.
+// The pdf url lives outside of #divJudgement, so it is carried over from the stored metadata.
+func reparseMetadata(extraction repository.Extraction, urlFrontier repository.UrlFrontier) (models.Metadata, error) {
+	reparsed := extraction
+	reparsed.Metadata = models.Metadata{PdfUrl: extraction.Metadata.PdfUrl}
+	if err := parseJudgement(*extraction.SiteContent, &reparsed, &urlFrontier); err != nil {
+		return models.Metadata{}, err
+	}
+	return reparsed.Metadata, nil
+}
@@ -4,15 +4,22 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"lexicon/singapore-supreme-court-crawler/common"
 	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
 	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/document"
 	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/retry"
 	"lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/jackc/pgx/v5"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
@@ -23,74 +30,346 @@ type Scrapper interface {
 	Teardown()
 	Scrape(ctx context.Context, url string) error
 	ScrapeAll(ctx context.Context) error
+	RescrapeAll(ctx context.Context) error
 	ScrapeAllSequential(ctx context.Context) error
 	Consume(m jetstream.Msg) error
 }
 
 type ScrapperImpl struct {
 	browser *rod.Browser
+	pages   rod.Pool[rod.Page]
+	// HttpFetcher downloads the judgement pages over plain HTTP instead of the browser when set
+	HttpFetcher *document.HttpFetcher
+	// MaxDeliver is the number of deliveries of a message before Consume gives up on it
+	MaxDeliver int
+	// RetryPolicy schedules failed url frontiers, DefaultRetryPolicy when zero
+	RetryPolicy crawler_model.RetryPolicy
+	// LeaseDuration hides a url frontier from other scrappers while it is scraped
+	LeaseDuration time.Duration
+	// UserAgent identifies the browser pages, the browser's own when empty
+	UserAgent string
+	// Concurrency is the number of url frontiers scraped at once, DEFAULT_SCRAPE_CONCURRENCY when zero
+	Concurrency int
+	// FetchRetry retries the transient failures of page loads, downloads and uploads, DefaultPolicy when zero
+	FetchRetry retry.Policy
+	// Stats counts the retries and failed url frontiers of the run, nothing when nil
+	Stats *retry.Stats
 }
 
 func (c *ScrapperImpl) Setup() {
+	if c.HttpFetcher != nil {
+		return
+	}
 	c.browser = rod.New().MustConnect()
+	c.pages = rod.NewPagePool(c.concurrency())
 }
 
 func (c *ScrapperImpl) Teardown() {
+	if c.browser == nil {
+		return
+	}
+	c.pages.Cleanup(func(p *rod.Page) {
+		err := p.Close()
+		if err != nil {
+			log.Error().Err(err).Msg("Error closing page")
+		}
+	})
 	c.browser.MustClose()
 }
 
+// Consume scrapes the url frontier whose id is carried by a work queue message.
+// The url frontier is leased like in ScrapeAll, so workers and scrape runs sharing the database never scrape it twice at once.
 func (c *ScrapperImpl) Consume(m jetstream.Msg) error {
-	return nil
+	lease := func(ctx context.Context, id string) (repository.UrlFrontier, bool, error) {
+		return crawler_service.LeaseUrlFrontier(ctx, id, c.leaseDuration())
+	}
+	return c.consume(m, lease, c.scrapeOne)
 }
 
+// consume scrapes the url frontier leased by lease and acks m once it is scraped, or was already.
+// A url frontier that is not due yet or leased by another scrapper is not scraped, m is naked until it is available.
+// When scrape fails m is naked until the url frontier is due again.
+// m is terminated for an unknown or dead-lettered url frontier. A failed url frontier with attempts left is never given up on:
+// on the last delivery m is acked and the url frontier is published again by RequeueDueUrlFrontiers once it is due.
+func (c *ScrapperImpl) consume(
+	m jetstream.Msg,
+	lease func(ctx context.Context, id string) (repository.UrlFrontier, bool, error),
+	scrape func(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.UpdateUrlFrontierAttemptParams, error),
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), common.NATS_ACK_WAIT)
+	defer cancel()
+
+	id := string(m.Data())
+	urlFrontier, leased, err := lease(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if termErr := m.TermWithReason("unknown url frontier"); termErr != nil {
+				log.Error().Err(termErr).Msg("Error terminating message")
+			}
+			return fmt.Errorf("unknown url frontier %q", id)
+		}
+		c.redeliverLater(m, id, crawler_service.RedeliveryDelay(m))
+		return err
+	}
+
+	switch urlFrontier.Status {
+	case crawler_model.URL_FRONTIER_STATUS_CRAWLED:
+		log.Info().Msgf("Url frontier %s is already scraped", urlFrontier.ID)
+		return m.Ack()
+	case crawler_model.URL_FRONTIER_STATUS_DEAD:
+		if termErr := m.TermWithReason("url frontier is dead-lettered"); termErr != nil {
+			log.Error().Err(termErr).Msg("Error terminating message")
+		}
+		return fmt.Errorf("url frontier %s is dead-lettered", urlFrontier.ID)
+	}
+
+	if !leased {
+		availableAt := crawler_service.UrlFrontierAvailableAt(urlFrontier)
+		log.Info().Msgf("Url frontier %s is not due or leased until %s", urlFrontier.ID, availableAt.Format(time.RFC3339))
+		c.redeliverLater(m, urlFrontier.ID, max(time.Until(availableAt), 0))
+		return nil
+	}
+
+	attempt, err := scrape(ctx, urlFrontier)
+	if err != nil {
+		if attempt.Status == crawler_model.URL_FRONTIER_STATUS_DEAD {
+			log.Error().Err(err).Msgf("Giving up on url frontier %s", urlFrontier.ID)
+			if termErr := m.TermWithReason(err.Error()); termErr != nil {
+				log.Error().Err(termErr).Msg("Error terminating message")
+			}
+			return err
+		}
+
+		delay := crawler_service.RedeliveryDelay(m)
+		if attempt.NextAttemptAt != nil {
+			delay = time.Until(*attempt.NextAttemptAt)
+		}
+		c.redeliverLater(m, urlFrontier.ID, delay)
+		return err
+	}
+
+	return m.Ack()
+}
+
+// redeliverLater naks m to deliver it again after delay. The last delivery is acked instead: the url frontier
+// keeps its schedule in the database and RequeueDueUrlFrontiers publishes it again once it is due.
+func (c *ScrapperImpl) redeliverLater(m jetstream.Msg, id string, delay time.Duration) {
+	maxDeliver := c.MaxDeliver
+	if maxDeliver == 0 {
+		maxDeliver = common.NATS_DEFAULT_MAX_DELIVER
+	}
+	if crawler_service.IsLastDelivery(m, maxDeliver) {
+		log.Warn().Msgf("Last delivery of url frontier %s, it is requeued once due", id)
+		if ackErr := m.Ack(); ackErr != nil {
+			log.Error().Err(ackErr).Msg("Error acking message")
+		}
+		return
+	}
+
+	if nakErr := m.NakWithDelay(delay); nakErr != nil {
+		log.Error().Err(nakErr).Msg("Error naking message")
+	}
+}
+
+// Scrape scrapes a single judgement url, creating its url frontier when the url was never crawled.
 func (c *ScrapperImpl) Scrape(ctx context.Context, url string) error {
-	return nil
+	urlFrontier, err := findOrCreateUrlFrontier(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.scrapeOne(ctx, urlFrontier)
+	return err
 }
 
-func (c *ScrapperImpl) ScrapeAll(ctx context.Context) error {
+// scrapeOne scrapes a url frontier, stores the extraction and records the attempt.
+func (c *ScrapperImpl) scrapeOne(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.UpdateUrlFrontierAttemptParams, error) {
+	extraction, err := c.scrapeUrlFrontier(ctx, urlFrontier)
+	if err != nil {
+		err = fmt.Errorf("error scraping %s: %w", urlFrontier.Url, err)
+	}
 
-	// Create a new context with cancellation
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel() // Ensure all resources are cleaned up
+	results := []scrapeResult{{UrlFrontier: urlFrontier, Extraction: extraction, Err: err}}
+	attempts, storeErr := c.storeResults(ctx, results)
+	if storeErr != nil {
+		return repository.UpdateUrlFrontierAttemptParams{}, errors.Join(results[0].Err, storeErr)
+	}
+	if len(attempts) == 0 {
+		return repository.UpdateUrlFrontierAttemptParams{}, results[0].Err
+	}
+	if results[0].Err != nil {
+		return attempts[0], results[0].Err
+	}
 
-	var unscrappedUrlFrontiers []repository.UrlFrontier
-	// Fetch unscrapped url frontier from db
+	log.Info().Msgf("Scraped %s into extraction %s", urlFrontier.Url, extraction.ID)
+	return attempts[0], nil
+}
 
-	pagePool := rod.NewPagePool(10)
-	defer pagePool.Cleanup(func(p *rod.Page) {
-		err := p.Close()
-		if err != nil {
-			log.Error().Err(err).Msg("Error closing page")
+func (c *ScrapperImpl) newPage() (*rod.Page, error) {
+	incognito, err := c.browser.Incognito()
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating incognito page")
+		return nil, err
+	}
+	page, err := incognito.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating page")
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: c.UserAgent}); err != nil {
+			log.Error().Err(err).Msg("Error setting user agent")
+			return nil, err
 		}
-	})
+	}
+	return page, nil
+}
 
-	create := func() (*rod.Page, error) {
-		incognito, err := c.browser.Incognito()
-		if err != nil {
-			log.Error().Err(err).Msg("Error creating incognito page")
-			return nil, err
+func (c *ScrapperImpl) retryPolicy() crawler_model.RetryPolicy {
+	if c.RetryPolicy == (crawler_model.RetryPolicy{}) {
+		return crawler_model.DefaultRetryPolicy()
+	}
+	return c.RetryPolicy
+}
+
+func (c *ScrapperImpl) leaseDuration() time.Duration {
+	if c.LeaseDuration <= 0 {
+		return common.DEFAULT_LEASE_DURATION
+	}
+	return c.LeaseDuration
+}
+
+func (c *ScrapperImpl) fetchRetry() retry.Policy {
+	if c.FetchRetry == (retry.Policy{}) {
+		return retry.DefaultPolicy()
+	}
+	return c.FetchRetry
+}
+
+func (c *ScrapperImpl) concurrency() int {
+	if c.Concurrency <= 0 {
+		return common.DEFAULT_SCRAPE_CONCURRENCY
+	}
+	return c.Concurrency
+}
+
+// scrapeResult is the outcome of one scrape of a url frontier, Err is nil when Extraction holds the result.
+type scrapeResult struct {
+	UrlFrontier repository.UrlFrontier
+	Extraction  repository.Extraction
+	Err         error
+}
+
+// storeResults upserts the extractions of the successful results and records the attempt of every url frontier.
+// A result whose extraction cannot be stored counts as failed. Results interrupted by a cancelled context are not recorded.
+func (c *ScrapperImpl) storeResults(ctx context.Context, results []scrapeResult) ([]repository.UpdateUrlFrontierAttemptParams, error) {
+	storeExtractions(ctx, results)
+
+	policy := c.retryPolicy()
+	now := time.Now()
+	attempts := []repository.UpdateUrlFrontierAttemptParams{}
+	for _, result := range results {
+		if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded) {
+			continue
 		}
-		return incognito.MustPage(), nil
+		if result.Err != nil {
+			log.Error().Err(result.Err).Msgf("Error scraping url frontier %s", result.UrlFrontier.ID)
+			c.Stats.Failed(result.Err)
+		}
+		attempts = append(attempts, crawler_service.NewUrlFrontierAttempt(policy, result.UrlFrontier, result.Err, now))
 	}
 
-	job := func(ctx context.Context, urlFrontier repository.UrlFrontier, c chan<- repository.Extraction) error {
-		page, err := pagePool.Get(create)
-		if err != nil {
-			log.Error().Err(err).Msg("Error getting page")
-			return err
+	if len(attempts) == 0 {
+		return attempts, nil
+	}
+
+	log.Info().Msgf("Recording %d url frontier attempts", len(attempts))
+	if err := crawler_service.UpdateUrlFrontierAttempts(ctx, attempts); err != nil {
+		log.Error().Err(err).Msg("Error recording url frontier attempts")
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+// storeExtractions upserts the extractions of the successful results, marking them failed when they cannot be stored.
+func storeExtractions(ctx context.Context, results []scrapeResult) {
+	var extractions []repository.Extraction
+	for _, result := range results {
+		if result.Err == nil {
+			extractions = append(extractions, result.Extraction)
 		}
-		defer pagePool.Put(page)
-		extraction, err := scrapeUrlFrontiers(ctx, page, urlFrontier)
-		if err != nil {
-			log.Error().Err(err).Msg("Error scraping url frontier")
-			return err
+	}
+	if len(extractions) == 0 {
+		return
+	}
+
+	log.Info().Msgf("Upserting %d extractions", len(extractions))
+	amendedIds, err := services.UpsertExtraction(ctx, extractions)
+	if err != nil {
+		log.Error().Err(err).Msg("Error upserting extractions")
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("error upserting extraction: %w", err)
+			}
 		}
-		c <- extraction
-		return nil
+		return
+	}
+
+	for _, id := range amendedIds {
+		log.Warn().Msgf("Judgement %s was amended, previous version archived", id)
+	}
+}
+
+// findOrCreateUrlFrontier looks up the url frontier of a judgement url by its id.
+// A url that was never crawled gets a new frontier with the metadata readable from the url itself.
+func findOrCreateUrlFrontier(ctx context.Context, url string) (repository.UrlFrontier, error) {
+	judgementUrl, err := normalizeJudgementUrl(url)
+	if err != nil {
+		return repository.UrlFrontier{}, err
+	}
+
+	id := crawler_model.UrlFrontierId(judgementUrl)
+	urlFrontier, err := crawler_service.GetUrlFrontierById(ctx, id)
+	if err == nil {
+		return urlFrontier, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return repository.UrlFrontier{}, err
 	}
 
-	for ok := true; ok; ok = len(unscrappedUrlFrontiers) > 0 {
+	log.Info().Msgf("No url frontier for %s, creating one", judgementUrl)
+	now := time.Now()
+	metadata := crawler_model.UrlFrontierMetadata{Citation: citationFromJudgementUrl(judgementUrl), Categories: []string{}, CaseNumbers: []string{}}
+	if metadata.Citation != nil {
+		metadata.CitationNumber = metadata.Citation.String()
+	}
+	urlFrontier = repository.UrlFrontier{
+		ID:        id,
+		Domain:    common.CRAWLER_DOMAIN,
+		Url:       judgementUrl,
+		Crawler:   common.CRAWLER_NAME,
+		Status:    crawler_model.URL_FRONTIER_STATUS_NEW,
+		Metadata:  metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := crawler_service.UpsertUrl(ctx, []repository.UrlFrontier{urlFrontier}); err != nil {
+		return repository.UrlFrontier{}, err
+	}
+
+	return urlFrontier, nil
+}
+
+func (c *ScrapperImpl) ScrapeAll(ctx context.Context) error {
 
+	// Create a new context with cancellation
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Ensure all resources are cleaned up
+
+	// Lease one chunk at a time so concurrent scrappers share the queue
+	cursor := crawler_service.NewUrlFrontierCursor(int32(c.concurrency()), c.leaseDuration())
+	for {
 		// Check if context is cancelled before starting new chunk
 		select {
 		case <-ctx.Done():
@@ -98,84 +377,105 @@ func (c *ScrapperImpl) ScrapeAll(ctx context.Context) error {
 		default:
 		}
 
-		var scraperErrors []error
-		unscrappedUrlFrontiers, err := crawler_service.GetUnscrappedUrlFrontiers(ctx, 100)
+		urlFrontiers, err := cursor.Next(ctx)
 		if err != nil {
-			log.Error().Err(err).Msg("Error fetching unscrapped url frontier")
+			log.Error().Err(err).Msg("Error leasing unscrapped url frontiers")
+			return err
 		}
-		log.Info().Msgf("Unscrapped URLs: %d", len(unscrappedUrlFrontiers))
-
-		chunks := lo.Chunk(unscrappedUrlFrontiers, 10)
-
-		for _, urlFrontiers := range chunks {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+		if len(urlFrontiers) == 0 {
+			break
+		}
+		log.Info().Msgf("Leased %d unscrapped URLs", len(urlFrontiers))
 
-			wg := sync.WaitGroup{}
-			extractionChan := make(chan repository.Extraction, len(urlFrontiers))
-			errChan := make(chan error, len(urlFrontiers))
-
-			for _, urlFrontier := range urlFrontiers {
-				wg.Add(1)
-				go func(urlFrontier repository.UrlFrontier) {
-					defer wg.Done()
-
-					select {
-					case <-ctx.Done():
-						errChan <- ctx.Err()
-					default:
-						if err := job(ctx, urlFrontier, extractionChan); err != nil {
-							errChan <- fmt.Errorf("error crawling %s: %w", urlFrontier.Url, err)
-						}
-					}
-				}(urlFrontier)
-			}
+		results := c.scrapeChunk(ctx, urlFrontiers)
+		if _, err := c.storeResults(ctx, results); err != nil {
+			log.Error().Err(err).Msg("Error storing scrape results")
+		}
+		log.Info().Msgf("Finished scraping chunk")
+	}
+	log.Info().Msgf("Scraped %d url frontiers", cursor.Leased())
+	log.Info().Msgf("Scrape summary: %s", c.Stats.Summary())
+	log.Info().Msgf("Finished scraping all url frontiers")
+	return nil
+}
 
-			// Wait for all goroutines to finish
-			wg.Wait()
+// RescrapeAll scrapes every already scraped url frontier again to detect amended judgements.
+// A failed re-scrape keeps the stored extraction and the status of its url frontier.
+func (c *ScrapperImpl) RescrapeAll(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-			// Close channels after all goroutines are done
-			close(errChan)
-			close(extractionChan)
+	afterId, rescraped, failed := "", 0, 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
 
-			// Collect errors and extractions
-			for err := range errChan {
-				scraperErrors = append(scraperErrors, err)
+		urlFrontiers, err := crawler_service.ListScrappedUrlFrontiers(ctx, afterId, int32(c.concurrency()))
+		if err != nil {
+			log.Error().Err(err).Msg("Error listing scrapped url frontiers")
+			return err
+		}
+		if len(urlFrontiers) == 0 {
+			break
+		}
+		afterId = urlFrontiers[len(urlFrontiers)-1].ID
+
+		results := c.scrapeChunk(ctx, urlFrontiers)
+		storeExtractions(ctx, results)
+		for _, result := range results {
+			if result.Err != nil {
+				log.Error().Err(result.Err).Msgf("Error re-scraping url frontier %s", result.UrlFrontier.ID)
+				c.Stats.Failed(result.Err)
+				failed++
+				continue
 			}
+			rescraped++
+		}
+	}
+	log.Info().Msgf("Re-scraped %d url frontiers, %d failed", rescraped, failed)
+	log.Info().Msgf("Scrape summary: %s", c.Stats.Summary())
+	return nil
+}
 
-			var extractions []repository.Extraction
-			for extraction := range extractionChan {
-				extractions = append(extractions, extraction)
-			}
+// scrapeChunk scrapes url frontiers concurrently.
+func (c *ScrapperImpl) scrapeChunk(ctx context.Context, urlFrontiers []repository.UrlFrontier) []scrapeResult {
+	job := func(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.Extraction, error) {
+		extraction, err := c.scrapeUrlFrontier(ctx, urlFrontier)
+		if err != nil {
+			return repository.Extraction{}, fmt.Errorf("error scraping %s: %w", urlFrontier.Url, err)
+		}
+		return extraction, nil
+	}
 
-			if len(scraperErrors) > 0 {
-				log.Error().Err(scraperErrors[0]).Msg("Error scraping url frontier")
-			}
-			log.Info().Msgf("Upserting extractions")
-			err := services.UpsertExtraction(ctx, extractions)
-			if err != nil {
-				log.Error().Err(err).Msg("Error upserting extractions")
-			}
-			log.Info().Msgf("Updating url frontier statuses")
-			err = crawler_service.UpdateFrontierStatuses(ctx, lo.Map(urlFrontiers, func(urlFrontier repository.UrlFrontier, _ int) lo.Tuple2[string, int16] {
-				return lo.Tuple2[string, int16]{A: urlFrontier.ID, B: crawler_model.URL_FRONTIER_STATUS_CRAWLED}
-			}))
-			if err != nil {
-				log.Error().Err(err).Msg("Error updating url frontier status")
-			}
-			log.Info().Msgf("Finished scraping chunk")
+	wg := sync.WaitGroup{}
+	results := make([]scrapeResult, len(urlFrontiers))
 
-		}
+	for i, urlFrontier := range urlFrontiers {
+		wg.Add(1)
+		go func(i int, urlFrontier repository.UrlFrontier) {
+			defer wg.Done()
 
+			result := scrapeResult{UrlFrontier: urlFrontier}
+			select {
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+			default:
+				result.Extraction, result.Err = job(ctx, urlFrontier)
+			}
+			results[i] = result
+		}(i, urlFrontier)
 	}
-	log.Info().Msgf("Finished scraping all url frontiers")
-	return nil
+
+	// Wait for all goroutines to finish
+	wg.Wait()
+	return results
 }
 
-func scrapeUrlFrontiers(ctx context.Context, page *rod.Page, urlFrontier repository.UrlFrontier) (repository.Extraction, error) {
+// scrapeUrlFrontier downloads the judgement page of urlFrontier, parses it and uploads its artifacts.
+func (c *ScrapperImpl) scrapeUrlFrontier(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.Extraction, error) {
 
 	select {
 	case <-ctx.Done():
@@ -184,22 +484,16 @@ func scrapeUrlFrontiers(ctx context.Context, page *rod.Page, urlFrontier reposit
 	}
 	log.Info().Msgf("Scraping url: %s", urlFrontier.Url)
 
-	rpCtx := page.Context(ctx)
-	wait := rpCtx.MustWaitNavigation()
-	err := rpCtx.Navigate(urlFrontier.Url)
+	var siteContent, donwloadLink string
+	err := retry.Do(ctx, c.fetchRetry(), c.Stats, urlFrontier.Url, func(ctx context.Context) error {
+		var err error
+		siteContent, donwloadLink, err = c.fetchJudgement(ctx, urlFrontier.Url)
+		return err
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Error navigating to url")
 		return repository.Extraction{}, err
 	}
-	wait()
-
-	rp := rpCtx.MustWaitStable()
 
-	judgement, err := rp.Element("#divJudgement")
-	if err != nil {
-		log.Error().Err(err).Msg("Error getting judgement")
-		return repository.Extraction{}, err
-	}
 	now := time.Now()
 	var extraction repository.Extraction
 	extraction.ID = urlFrontier.ID
@@ -207,61 +501,177 @@ func scrapeUrlFrontiers(ctx context.Context, page *rod.Page, urlFrontier reposit
 	extraction.Language = "en"
 	extraction.CreatedAt = now
 	extraction.UpdatedAt = now
-	siteContent, err := judgement.HTML()
+	extraction.SiteContent = &siteContent
+	extraction.Metadata.PdfUrl = donwloadLink
+	hash, err := pageHash(siteContent)
 	if err != nil {
-		log.Error().Err(err).Msg("Error getting site content")
-		return repository.Extraction{}, err
+		return repository.Extraction{}, retry.Parse(err)
 	}
-	extraction.SiteContent = &siteContent
-	nav, err := rp.Element("nav")
+	extraction.PageHash = &hash
+
+	err = parseJudgement(siteContent, &extraction, &urlFrontier)
 	if err != nil {
-		log.Error().Err(err).Msg("Error getting nav")
-		return repository.Extraction{}, err
+		log.Error().Err(err).Msg("Error parsing judgement")
+		return repository.Extraction{}, retry.Parse(err)
 	}
-	donwloadLink, err := extractPdfUrl(nav)
+	stored, err := services.GetStoredArtifacts(ctx, extraction.ID)
 	if err != nil {
-		log.Error().Err(err).Msg("Error extracting pdf url")
 		return repository.Extraction{}, err
 	}
-	extraction.Metadata.PdfUrl = donwloadLink
-	hashPage := sha256.Sum256([]byte(*extraction.SiteContent))
-	hashPageString := hex.EncodeToString(hashPage[:])
-	extraction.PageHash = &hashPageString
 
-	content, err := judgement.Element("content")
-	if err != nil {
-		log.Info().Msg("Judgement is old")
-		err = scrapeOldTemplate(ctx, judgement, &extraction, &urlFrontier)
-		if err != nil {
-			log.Error().Err(err).Msg("Error scraping old template")
-			return repository.Extraction{}, err
-		}
-	} else {
-		err = scrapeNewTemplate(ctx, content, &extraction, &urlFrontier)
-		if err != nil {
-			log.Error().Err(err).Msg("Error scraping new template")
-			return repository.Extraction{}, err
-		}
-	}
 	log.Info().Msgf("Handling pdf for url: %s", urlFrontier.Url)
-	pdfUrl, err := services.HandlePdf(ctx, extraction.ID, extraction.Metadata.PdfUrl)
+	var pdf services.Artifact
+	err = retry.Do(ctx, c.fetchRetry(), c.Stats, extraction.Metadata.PdfUrl, func(ctx context.Context) error {
+		var err error
+		pdf, err = services.HandlePdf(ctx, extraction.ID, services.ArtifactSource{
+			Url:       extraction.Metadata.PdfUrl,
+			Citation:  extraction.Metadata.CitationNumber,
+			CrawledAt: now,
+		}, stored.Pdf)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Error handling pdf")
 		return repository.Extraction{}, err
 	}
-	log.Info().Msgf("Uploaded pdf for url: %s, to GCS: %s", urlFrontier.Url, pdfUrl.B)
+	logArtifact("pdf", urlFrontier.Url, pdf)
 	log.Info().Msgf("Downloading html for url: %s", urlFrontier.Url)
-	htmlUrl, err := services.HandleHtml(ctx, extraction.ID, urlFrontier.Url)
+	var html services.Artifact
+	err = retry.Do(ctx, c.fetchRetry(), c.Stats, urlFrontier.Url, func(ctx context.Context) error {
+		var err error
+		html, err = services.HandleHtml(ctx, extraction.ID, services.ArtifactSource{
+			Url:       urlFrontier.Url,
+			Citation:  extraction.Metadata.CitationNumber,
+			CrawledAt: now,
+		}, stored.Html)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Error handling html")
 		return repository.Extraction{}, err
 	}
-	log.Info().Msgf("Uploaded html for url: %s, to GCS: %s", urlFrontier.Url, htmlUrl.B)
-
-	extraction.ArtifactLink = &pdfUrl.B
-	extraction.RawPageLink = &htmlUrl.B
+	logArtifact("html", urlFrontier.Url, html)
+
+	extraction.ArtifactLink = &pdf.Link
+	extraction.ArtifactSha256 = &pdf.Sha256
+	extraction.ArtifactSize = &pdf.Size
+	extraction.ArtifactEtag = lo.EmptyableToPtr(pdf.ETag)
+	extraction.ArtifactLastModified = lo.EmptyableToPtr(pdf.LastModified)
+	extraction.RawPageLink = &html.Link
+	extraction.RawPageSha256 = &html.Sha256
+	extraction.RawPageSize = &html.Size
+	extraction.RawPageEtag = lo.EmptyableToPtr(html.ETag)
+	extraction.RawPageLastModified = lo.EmptyableToPtr(html.LastModified)
+
+	// The text of the PDF is a fallback for sparse pages, a PDF without one does not fail the scrape.
+	// An unchanged PDF keeps the text stored by a previous scrape.
+	if !pdf.Unchanged || !stored.HasPdfText {
+		text, err := downloadedPdfText(ctx, pdf)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Error extracting pdf text for url: %s", urlFrontier.Url)
+		} else {
+			setPdfText(&extraction, text)
+		}
+	}
 	log.Info().Msgf("Scraped template for url: %s", urlFrontier.Url)
 
 	return extraction, nil
 
 }
+
+func logArtifact(kind string, url string, artifact services.Artifact) {
+	if artifact.Unchanged {
+		log.Info().Msgf("Kept unchanged %s for url: %s, at: %s", kind, url, artifact.Link)
+		return
+	}
+	log.Info().Msgf("Uploaded %s for url: %s, to: %s", kind, url, artifact.Link)
+}
+
+// fetchJudgement downloads a judgement page and returns the HTML of #divJudgement and the url of its PDF.
+func (c *ScrapperImpl) fetchJudgement(ctx context.Context, url string) (string, string, error) {
+	if c.HttpFetcher != nil {
+		doc, err := c.HttpFetcher.Fetch(ctx, url)
+		if err != nil {
+			log.Error().Err(err).Msg("Error fetching url")
+			return "", "", err
+		}
+		return readJudgement(document.FromSelection(doc.Selection))
+	}
+
+	page, err := c.pages.Get(c.newPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting page")
+		return "", "", err
+	}
+	defer c.pages.Put(page)
+
+	doc, err := browseJudgement(ctx, page, url)
+	if err != nil {
+		return "", "", err
+	}
+	return readJudgement(doc)
+}
+
+// browseJudgement navigates to a judgement page in the browser and waits for it to settle.
+func browseJudgement(ctx context.Context, page *rod.Page, url string) (document.Node, error) {
+	if err := common.WaitForRequest(ctx, url); err != nil {
+		return nil, err
+	}
+
+	rpCtx := page.Context(ctx)
+	wait := rpCtx.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
+	err := rpCtx.Navigate(url)
+	if err != nil {
+		log.Error().Err(err).Msg("Error navigating to url")
+		return nil, retry.Transient(err)
+	}
+	wait()
+
+	if err := rpCtx.WaitStable(time.Second); err != nil {
+		log.Error().Err(err).Msg("Error waiting for page to be stable")
+		return nil, retry.Transient(err)
+	}
+
+	return document.FromPage(rpCtx)
+}
+
+// pageHash is the sha256 hash of the text of a judgement page with its whitespace collapsed.
+// The browser and the http fetcher serialise the same page into different HTML, but not into different text,
+// so switching fetchers does not make every judgement look amended.
+func pageHash(siteContent string) (string, error) {
+	doc, err := document.Parse(siteContent)
+	if err != nil {
+		return "", err
+	}
+	text, err := doc.Text()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// readJudgement reads the HTML of #divJudgement and the url of its PDF from a judgement page.
+func readJudgement(doc document.Node) (string, string, error) {
+	judgement, err := doc.Find("#divJudgement")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting judgement")
+		return "", "", err
+	}
+	siteContent, err := judgement.HTML()
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting site content")
+		return "", "", err
+	}
+	nav, err := doc.Find("nav")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting nav")
+		return "", "", err
+	}
+	donwloadLink, err := extractPdfUrl(nav)
+	if err != nil {
+		log.Error().Err(err).Msg("Error extracting pdf url")
+		return "", "", err
+	}
+	return siteContent, donwloadLink, nil
+}
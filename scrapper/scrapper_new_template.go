@@ -1,57 +1,46 @@
 package scrapper
 
 import (
-	"context"
-	"errors"
 	"strings"
 	"time"
 
+	"lexicon/singapore-supreme-court-crawler/document"
 	"lexicon/singapore-supreme-court-crawler/repository"
 
-	md "github.com/JohannesKaufmann/html-to-markdown"
-	mdp "github.com/JohannesKaufmann/html-to-markdown/plugin"
-	gq "github.com/PuerkitoBio/goquery"
-	"github.com/go-rod/rod"
 	"github.com/rs/zerolog/log"
 )
 
-func scrapeNewTemplate(ctx context.Context, e *rod.Element, extraction *repository.Extraction, urlFrontier *repository.UrlFrontier) error {
+func parseNewTemplate(e document.Node, extraction *repository.Extraction, urlFrontier *repository.UrlFrontier) error {
 
-	select {
-	case <-ctx.Done():
-		return errors.New("context canceled")
-	default:
-	}
-	log.Info().Msgf("Scraping new template for url: %s", urlFrontier.Url)
+	log.Info().Msgf("Parsing new template for url: %s", urlFrontier.Url)
 
 	extraction.Metadata.CitationNumber = urlFrontier.Metadata.CitationNumber
-	extraction.Metadata.Numbers = urlFrontier.Metadata.CaseNumbers
+	extraction.Metadata.Numbers = parseCaseNumbers(urlFrontier.Metadata.CaseNumbers)
 	extraction.Metadata.Classifications = urlFrontier.Metadata.Categories
-	year, err := time.Parse(time.RFC3339, urlFrontier.Metadata.DecisionDate)
-	if err != nil {
-		log.Error().Err(err).Msg("Error parsing year")
-		return err
+	// Frontiers created from a bare judgement url have no decision date
+	if urlFrontier.Metadata.DecisionDate != "" {
+		year, err := time.Parse(time.RFC3339, urlFrontier.Metadata.DecisionDate)
+		if err != nil {
+			log.Error().Err(err).Msg("Error parsing year")
+			return err
+		}
+		extraction.Metadata.Year = year.Format("2006")
 	}
-	extraction.Metadata.Year = year.Format("2006")
 	extraction.Metadata.DecisionDate = urlFrontier.Metadata.DecisionDate
 	extraction.Metadata.Title = urlFrontier.Metadata.Title
-	splittedTitle := strings.Split(extraction.Metadata.Title, " v ")
-
-	for _, part := range splittedTitle {
-		if strings.Contains(strings.ToLower(part), "public prosecutor") {
-			continue
-		}
-
-		extraction.Metadata.Defendant = strings.TrimSpace(part)
-	}
-	corams, err := e.Elements("div.HN-Coram")
+	corams, err := e.FindAll("div.HN-Coram")
 	if err != nil {
 		log.Error().Err(err).Msg("Error getting coram")
 		return err
 	}
-
+	var dateLines []string
 	for _, coram := range corams {
-		splittedCoram := strings.Split(coram.MustText(), "\n")
+		coramText, err := coram.Text()
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting coram")
+			return err
+		}
+		splittedCoram := strings.Split(coramText, "\n")
 
 		for i, part := range splittedCoram {
 			if i == 0 {
@@ -61,124 +50,65 @@ func scrapeNewTemplate(ctx context.Context, e *rod.Element, extraction *reposito
 			}
 			if i == 1 {
 				extraction.Metadata.Judges = strings.TrimSpace(part)
+				extraction.Metadata.Coram = parseCoram(extraction.Metadata.Judges)
 				continue
 			}
-
+			dateLines = append(dateLines, part)
 		}
 	}
+	extraction.Metadata.HearingDates, extraction.Metadata.ReservedDates = parseHeaderDates(dateLines, extraction.Metadata.DecisionDate)
 
-	var rawVerdict []string
-	var markdownVerdict []string
-	converter := md.NewConverter("", true, nil)
-	converter.Use(mdp.GitHubFlavored())
-
-	converter.AddRules(
-		md.Rule{
-			Filter: []string{"p.Judg-Author"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("** " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-Heading-1"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("# " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-Heading-2"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("## " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-1", "p.Judg-2", "p.Judg-List-1-Item", "p.Judg-List-2-Item"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String(content)
-			},
-		},
-
-		md.Rule{
-			Filter: []string{"p.Judge-Quote-1"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("> " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judge-Quote-2"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String(">> " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-QuoteList-2"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("- " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-QuoteList-3"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("  - " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Footnote"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("^" + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-List-1-No"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("1. " + content)
-			},
-		},
-		md.Rule{
-			Filter: []string{"p.Judg-List-2-No"},
-			Replacement: func(content string, selec *gq.Selection, options *md.Options) *string {
-				content = strings.TrimSpace(content)
-				return md.String("a. " + content)
-			},
-		},
-	)
-
-	verdicts, err := e.Elements("div.col.col-md-12.align-self-center")
+	parties, err := e.FindAll("div.HN-Parties")
 	if err != nil {
-		log.Error().Err(err).Msg("Error getting verdicts")
+		log.Error().Err(err).Msg("Error getting parties")
 		return err
 	}
-
-	for _, verdict := range verdicts {
-
-		rawVerdict = append(rawVerdict, verdict.MustText())
-		html, err := verdict.HTML()
+	for _, party := range parties {
+		partyText, err := party.Text()
 		if err != nil {
-			log.Error().Err(err).Msg("Error getting html")
-			continue
+			log.Error().Err(err).Msg("Error getting parties")
+			return err
 		}
-		md, err := converter.ConvertString(html)
+		extraction.Metadata.Parties = append(extraction.Metadata.Parties, parseParties(partyText)...)
+	}
+	// Judgements without a parties section name them in the title only
+	if len(parties) == 0 {
+		log.Warn().Msgf("No HN-Parties section in %s, taking the parties from the title", urlFrontier.Url)
+	}
+	if len(extraction.Metadata.Parties) == 0 {
+		extraction.Metadata.Parties = parseTitleParties(extraction.Metadata.Title, extraction.Metadata.Numbers)
+	}
+	extraction.Metadata.Defendant = defendantOf(extraction.Metadata.Parties)
+
+	counsels, err := e.FindAll("div.HN-Counsel")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting counsel")
+		return err
+	}
+	if len(counsels) == 0 {
+		log.Warn().Msgf("No HN-Counsel section in %s", urlFrontier.Url)
+	}
+	var counselLines []string
+	for _, counsel := range counsels {
+		counselText, err := counsel.Text()
 		if err != nil {
-			log.Error().Err(err).Msg("Error converting verdict")
-			continue
+			log.Error().Err(err).Msg("Error getting counsel")
+			return err
 		}
+		counselLines = append(counselLines, counselText)
+	}
+	extraction.Metadata.Counsel = strings.Join(counselLines, "\n")
+	extraction.Metadata.CounselByParty = parseCounsel(extraction.Metadata.Counsel)
 
-		markdownVerdict = append(markdownVerdict, md)
-
+	verdicts, err := e.FindAll("div.col.col-md-12.align-self-center")
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting verdict")
+		return err
 	}
+	rawVerdict, markdownVerdict := convertVerdict(verdicts)
 	extraction.Metadata.Verdict = strings.Join(rawVerdict, "\n")
 	extraction.Metadata.VerdictMarkdown = strings.Join(markdownVerdict, "\n")
-	log.Info().Msgf("Scraped new template for url: %s", urlFrontier.Url)
+	log.Info().Msgf("Parsed new template for url: %s", urlFrontier.Url)
 
 	return nil
 }
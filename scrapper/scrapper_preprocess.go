@@ -2,36 +2,68 @@ package scrapper
 
 import (
 	"fmt"
+	"lexicon/singapore-supreme-court-crawler/citation"
 	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/document"
+	stdUrl "net/url"
 	"path"
 	"strings"
 
-	"github.com/go-rod/rod"
 	"github.com/rs/zerolog/log"
 )
 
-func extractPdfUrl(e *rod.Element) (string, error) {
-	hrefs, err := e.Elements("a[href]")
+// extractPdfUrl returns the url of the first link to a PDF in e.
+func extractPdfUrl(e document.Node) (string, error) {
+	hrefs, err := e.FindAll("a[href]")
 	if err != nil {
 		return "", fmt.Errorf("failed to find href elements: %w", err)
 	}
 
 	for _, href := range hrefs {
-		attr, err := href.Attribute("href")
+		attr, _, err := href.Attr("href")
 		if err != nil {
 			return "", fmt.Errorf("failed to get href attribute: %w", err)
 		}
-		if strings.Contains(*attr, "pdf") {
-			// Sanitize the path to prevent path traversal
-			cleanPath := path.Clean(*attr)
-			if strings.HasPrefix(cleanPath, "..") {
-				return "", fmt.Errorf("invalid path: %s", *attr)
-			}
-			pdfUrl := fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, cleanPath)
-			log.Info().Msg("Found PDF: " + pdfUrl)
-			return pdfUrl, nil
+		if strings.Contains(attr, "pdf") {
+			return pdfUrlFromHref(attr)
 		}
 	}
 
 	return "", fmt.Errorf("no pdf url found in element")
 }
+
+func pdfUrlFromHref(href string) (string, error) {
+	// Sanitize the path to prevent path traversal
+	cleanPath := path.Clean(href)
+	if strings.HasPrefix(cleanPath, "..") {
+		return "", fmt.Errorf("invalid path: %s", href)
+	}
+	pdfUrl := fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, cleanPath)
+	log.Info().Msg("Found PDF: " + pdfUrl)
+	return pdfUrl, nil
+}
+
+// normalizeJudgementUrl turns a judgement link or url into the absolute url stored in the url frontier.
+func normalizeJudgementUrl(rawUrl string) (string, error) {
+	u, err := stdUrl.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return "", fmt.Errorf("invalid judgement url %s: %w", rawUrl, err)
+	}
+	if u.Host != "" && u.Host != common.CRAWLER_DOMAIN {
+		return "", fmt.Errorf("judgement url %s is not on %s", rawUrl, common.CRAWLER_DOMAIN)
+	}
+	if !strings.HasPrefix(u.Path, "/gd/s/") {
+		return "", fmt.Errorf("url %s is not a judgement page", rawUrl)
+	}
+
+	return fmt.Sprintf("https://%s%s", common.CRAWLER_DOMAIN, u.Path), nil
+}
+
+// citationFromJudgementUrl reads the neutral citation from a judgement url such as /gd/s/2023_SGHC_123, nil without one.
+func citationFromJudgementUrl(judgementUrl string) *citation.Citation {
+	c, err := citation.FromSlug(path.Base(judgementUrl))
+	if err != nil {
+		return nil
+	}
+	return &c
+}
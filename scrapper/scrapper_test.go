@@ -0,0 +1,160 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/common/natstest"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func startScrapperQueue(t *testing.T, urlFrontierId string) (*natstest.Watcher, jetstream.Consumer) {
+	t.Helper()
+	nc, _ := natstest.Start(t)
+	ctx := context.Background()
+	if err := crawler_service.EnsureStream(ctx); err != nil {
+		t.Fatal(err)
+	}
+	consumer, err := crawler_service.EnsureConsumer(ctx, common.NATS_SCRAPPER_CONSUMER, common.NATS_URL_FRONTIER_SUBJECT, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crawler_service.PublishUrlFrontiers(ctx, []repository.UrlFrontier{{ID: urlFrontierId}}); err != nil {
+		t.Fatal(err)
+	}
+	return natstest.Watch(t, nc, consumer), consumer
+}
+
+func TestConsume(t *testing.T) {
+	soon := time.Now()
+	later := time.Now().Add(time.Hour)
+	tests := []struct {
+		name        string
+		urlFrontier repository.UrlFrontier
+		notLeased   bool
+		leaseErr    error
+		attempt     repository.UpdateUrlFrontierAttemptParams
+		scrapeErr   error
+		want        string
+		wantScraped bool
+	}{
+		{name: "scraped", want: natstest.ACKED, wantScraped: true},
+		{
+			name:        "already scraped",
+			urlFrontier: repository.UrlFrontier{Status: crawler_model.URL_FRONTIER_STATUS_CRAWLED},
+			notLeased:   true,
+			want:        natstest.ACKED,
+		},
+		{name: "unknown url frontier", leaseErr: pgx.ErrNoRows, want: natstest.TERMINATED},
+		{name: "lease failed", leaseErr: errors.New("connection refused"), want: natstest.NAKED},
+		{
+			name:        "dead-lettered",
+			urlFrontier: repository.UrlFrontier{Status: crawler_model.URL_FRONTIER_STATUS_DEAD},
+			notLeased:   true,
+			want:        natstest.TERMINATED,
+		},
+		{
+			name:        "not due yet",
+			urlFrontier: repository.UrlFrontier{Status: crawler_model.URL_FRONTIER_STATUS_ERROR, NextAttemptAt: &later},
+			notLeased:   true,
+			want:        natstest.NAKED,
+		},
+		{
+			name:        "leased by another scrapper",
+			urlFrontier: repository.UrlFrontier{LeasedUntil: &later},
+			notLeased:   true,
+			want:        natstest.NAKED,
+		},
+		{
+			name:        "failed",
+			attempt:     repository.UpdateUrlFrontierAttemptParams{Status: crawler_model.URL_FRONTIER_STATUS_ERROR, NextAttemptAt: &soon},
+			scrapeErr:   errors.New("timeout"),
+			want:        natstest.NAKED,
+			wantScraped: true,
+		},
+		{
+			name:        "failed into the dead letters",
+			attempt:     repository.UpdateUrlFrontierAttemptParams{Status: crawler_model.URL_FRONTIER_STATUS_DEAD},
+			scrapeErr:   errors.New("timeout"),
+			want:        natstest.TERMINATED,
+			wantScraped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher, consumer := startScrapperQueue(t, "a")
+
+			scraped := false
+			c := &ScrapperImpl{MaxDeliver: 2}
+			err := c.consume(natstest.Next(t, consumer),
+				func(ctx context.Context, id string) (repository.UrlFrontier, bool, error) {
+					urlFrontier := tt.urlFrontier
+					urlFrontier.ID = id
+					return urlFrontier, !tt.notLeased && tt.leaseErr == nil, tt.leaseErr
+				},
+				func(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.UpdateUrlFrontierAttemptParams, error) {
+					scraped = urlFrontier.ID == "a"
+					return tt.attempt, tt.scrapeErr
+				},
+			)
+			if wantErr := tt.want == natstest.TERMINATED || tt.leaseErr != nil || tt.scrapeErr != nil; (err != nil) != wantErr {
+				t.Errorf("consume() error = %v", err)
+			}
+			if got := watcher.Outcome(t); got != tt.want {
+				t.Errorf("message outcome = %s, want %s", got, tt.want)
+			}
+			if scraped != tt.wantScraped {
+				t.Errorf("scraped = %v, want %v", scraped, tt.wantScraped)
+			}
+		})
+	}
+}
+
+func TestConsumeMaxDeliver(t *testing.T) {
+	watcher, consumer := startScrapperQueue(t, "a")
+
+	c := &ScrapperImpl{MaxDeliver: 2}
+	lease := func(ctx context.Context, id string) (repository.UrlFrontier, bool, error) {
+		return repository.UrlFrontier{ID: id}, true, nil
+	}
+	scrape := func(ctx context.Context, urlFrontier repository.UrlFrontier) (repository.UpdateUrlFrontierAttemptParams, error) {
+		now := time.Now()
+		return repository.UpdateUrlFrontierAttemptParams{Status: crawler_model.URL_FRONTIER_STATUS_ERROR, NextAttemptAt: &now}, errors.New("timeout")
+	}
+
+	// The failed first delivery is due again at once, the second is the last one and is left to the requeue
+	for delivery, want := range []string{natstest.NAKED, natstest.ACKED} {
+		if err := c.consume(natstest.Next(t, consumer), lease, scrape); err == nil {
+			t.Errorf("delivery %d: consume() error = nil", delivery+1)
+		}
+		if got := watcher.Outcome(t); got != want {
+			t.Errorf("delivery %d: message outcome = %s, want %s", delivery+1, got, want)
+		}
+	}
+}
+
+func TestPageHash(t *testing.T) {
+	browser := `<div id="divJudgement" class="row"><div class='judgment'>
+		<p>Public Prosecutor   v Tan Ah Kow</p>
+	</div></div>`
+	http := `<div class="row" id="divJudgement"><div class="judgment"><p>Public Prosecutor v Tan Ah Kow</p></div></div>`
+	amended := `<div id="divJudgement"><div class="judgment"><p>Public Prosecutor v Tan Ah Kiow</p></div></div>`
+
+	browserHash, err := pageHash(browser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if httpHash, _ := pageHash(http); httpHash != browserHash {
+		t.Errorf("pageHash() differs between serialisations of the same page")
+	}
+	if amendedHash, _ := pageHash(amended); amendedHash == browserHash {
+		t.Errorf("pageHash() is the same for an amended page")
+	}
+}
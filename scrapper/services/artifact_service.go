@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Artifact is a downloaded file kept in the artifact store.
+type Artifact struct {
+	Link string
+	Size int64
+	// Sha256 is the hex digest of the content
+	Sha256 string
+	// ETag and LastModified are the validators of the download, sent back to request it again only when it changed
+	ETag         string
+	LastModified string
+	// Unchanged is set when the previous artifact was kept and nothing was uploaded
+	Unchanged bool
+	// Content is the download of a PDF, so its text is extracted without reading it back from the store.
+	// It is nil when the site answered 304 Not Modified.
+	Content []byte
+}
+
+// ArtifactSource describes where an artifact comes from, it is kept as custom metadata of the object.
+type ArtifactSource struct {
+	Url       string
+	Citation  string
+	CrawledAt time.Time
+}
+
+func (s ArtifactSource) metadata() map[string]string {
+	return map[string]string{
+		"source-url": s.Url,
+		"citation":   s.Citation,
+		"crawled-at": s.CrawledAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// HandlePdf streams the judgement PDF at source.Url to the artifact store.
+// previous, the PDF stored by the last scrape if any, is kept when the PDF did not change.
+func HandlePdf(ctx context.Context, name string, source ArtifactSource, previous *Artifact) (Artifact, error) {
+	artifact, err := streamArtifact(ctx, source, previous, fmt.Sprintf("%s/%s", common.GCS_FOLDER, sanitizeName(name)), "application/pdf", ValidatePdf, true)
+	if err != nil {
+		log.Error().Err(err).Msg("Error uploading pdf")
+		return Artifact{}, err
+	}
+	return artifact, nil
+}
+
+// HandleHtml streams the judgement page at source.Url to the artifact store.
+// previous, the page stored by the last scrape if any, is kept when the page did not change.
+func HandleHtml(ctx context.Context, name string, source ArtifactSource, previous *Artifact) (Artifact, error) {
+	artifact, err := streamArtifact(ctx, source, previous, fmt.Sprintf("%s/%s", common.GCS_HTML_FOLDER, sanitizeName(name)), "text/html", ValidateHtml, false)
+	if err != nil {
+		log.Error().Err(err).Msg("Error uploading html")
+		return Artifact{}, err
+	}
+	return artifact, nil
+}
+
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-zA-Z0-9 ]+`)
+
+func sanitizeName(name string) string {
+	fileName := nonAlphanumericRegex.ReplaceAllString(name, "_")
+	size := math.Min(float64(len(fileName)), float64(100))
+	return strings.ReplaceAll(fileName[:int(size)], " ", "_")
+}
+
+// streamArtifact downloads source.Url with common.HttpClient straight into the artifact store, without a local copy.
+// Content rejected by validate and permanent 4xx responses such as 404 fail with crawler_model.ErrInvalidArtifact and nothing is stored.
+// Rate limiting and server errors are only classified as transient, an outage of the site does not make the artifact invalid.
+// The size and MD5 reported by the store are checked against the downloaded content.
+//
+// With a previous artifact the download is conditional on its validators, and previous is kept when the site
+// answers 304 Not Modified. Otherwise the download is read in memory and only uploaded when its SHA-256 differs.
+// With keepContent the download is also returned as the Content of the artifact.
+func streamArtifact(ctx context.Context, source ArtifactSource, previous *Artifact, objectPath string, contentType string, validate func(r io.Reader, contentType string) error, keepContent bool) (Artifact, error) {
+	if common.Artifacts == nil {
+		return Artifact{}, errors.New("artifact store is not set")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source.Url, nil)
+	if err != nil {
+		return Artifact{}, err
+	}
+	if previous != nil && previous.ETag != "" {
+		request.Header.Set("If-None-Match", previous.ETag)
+	}
+	if previous != nil && previous.LastModified != "" {
+		request.Header.Set("If-Modified-Since", previous.LastModified)
+	}
+	response, err := common.HttpClient.Do(request)
+	if err != nil {
+		log.Error().Err(err).Msg("Error downloading " + source.Url)
+		return Artifact{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified && previous != nil {
+		unchanged := *previous
+		unchanged.Unchanged, unchanged.Content = true, nil
+		return unchanged, nil
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err := fmt.Errorf("unexpected status %d downloading %s", response.StatusCode, source.Url)
+		if response.StatusCode >= 400 && response.StatusCode < 500 && retry.StatusClass(response.StatusCode) == retry.ERROR_CLASS_PERMANENT {
+			err = fmt.Errorf("%w: %w", crawler_model.ErrInvalidArtifact, err)
+		}
+		return Artifact{}, retry.WithStatus(response.StatusCode, err)
+	}
+
+	r := newArtifactReader(response.Body, response.Header.Get("Content-Type"), validate)
+	defer r.Close()
+
+	var body io.Reader = r
+	var buffer bytes.Buffer
+	var content []byte
+	switch {
+	case previous != nil:
+		// The whole content is needed to compare it with the previous one before uploading anything
+		if _, err := io.Copy(&buffer, r); err != nil {
+			return Artifact{}, fmt.Errorf("%s: %w", source.Url, err)
+		}
+		if keepContent {
+			content = buffer.Bytes()
+		}
+		if r.Sha256() == previous.Sha256 {
+			return Artifact{
+				Link:         previous.Link,
+				Size:         previous.Size,
+				Sha256:       previous.Sha256,
+				ETag:         response.Header.Get("ETag"),
+				LastModified: response.Header.Get("Last-Modified"),
+				Unchanged:    true,
+				Content:      content,
+			}, nil
+		}
+		body = bytes.NewReader(buffer.Bytes())
+	case keepContent:
+		body = io.TeeReader(r, &buffer)
+	}
+
+	stored, err := common.Artifacts.Upload(ctx, objectPath, body, common.ArtifactAttrs{
+		ContentType: contentType,
+		Metadata:    source.metadata(),
+	})
+	if err != nil {
+		// The store may not wrap the error of the reader, which tells invalid artifacts apart
+		if readErr := r.Err(); readErr != nil {
+			return Artifact{}, fmt.Errorf("%s: %w", source.Url, readErr)
+		}
+		return Artifact{}, err
+	}
+
+	if stored.Size != r.Size() {
+		return Artifact{}, retry.Transient(fmt.Errorf("artifact %s stored with %d bytes, downloaded %d", stored.Link, stored.Size, r.Size()))
+	}
+	if stored.Md5 != nil && !bytes.Equal(stored.Md5, r.Md5()) {
+		return Artifact{}, retry.Transient(fmt.Errorf("artifact %s stored with a different MD5 than downloaded", stored.Link))
+	}
+
+	if keepContent {
+		content = buffer.Bytes()
+	}
+	return Artifact{
+		Link:         stored.Link,
+		Size:         stored.Size,
+		Sha256:       r.Sha256(),
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+		Content:      content,
+	}, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamArtifact(t *testing.T) {
+	pdf := "%PDF-1.7\n" + strings.Repeat("0", 64*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/judgement.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			io.WriteString(w, pdf)
+		case "/busy.pdf":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/down.pdf":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/error.pdf":
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "<html><body>"+strings.Repeat("Something went wrong ", 100)+"</body></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	store, err := NewLocalArtifactStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousStore, previousClient := common.Artifacts, common.HttpClient
+	common.Artifacts, common.HttpClient = store, server.Client()
+	t.Cleanup(func() {
+		common.Artifacts, common.HttpClient = previousStore, previousClient
+	})
+
+	source := ArtifactSource{Url: server.URL + "/judgement.pdf", Citation: "[2024] SGHC 1", CrawledAt: time.Now()}
+	artifact, err := HandlePdf(context.Background(), "2024 SGHC 1", source, nil)
+	if err != nil {
+		t.Fatalf("HandlePdf() error = %v", err)
+	}
+	digest := sha256.Sum256([]byte(pdf))
+	if artifact.Sha256 != hex.EncodeToString(digest[:]) {
+		t.Errorf("Sha256 = %s, want the digest of the download", artifact.Sha256)
+	}
+	if artifact.Size != int64(len(pdf)) {
+		t.Errorf("Size = %d, want %d", artifact.Size, len(pdf))
+	}
+	r, err := store.Open(context.Background(), artifact.Link)
+	if err != nil {
+		t.Fatalf("Open(%s) error = %v", artifact.Link, err)
+	}
+	stored, _ := io.ReadAll(r)
+	r.Close()
+	if string(stored) != pdf {
+		t.Errorf("stored %d bytes, want the downloaded PDF", len(stored))
+	}
+
+	for _, path := range []string{"/error.pdf", "/missing.pdf"} {
+		source.Url = server.URL + path
+		if _, err := HandlePdf(context.Background(), "invalid"+path, source, nil); !errors.Is(err, crawler_model.ErrInvalidArtifact) {
+			t.Errorf("HandlePdf(%s) error = %v, want ErrInvalidArtifact", path, err)
+		}
+	}
+	for _, path := range []string{"/busy.pdf", "/down.pdf"} {
+		source.Url = server.URL + path
+		_, err := HandlePdf(context.Background(), "unavailable"+path, source, nil)
+		if errors.Is(err, crawler_model.ErrInvalidArtifact) || !retry.IsTransient(err) {
+			t.Errorf("HandlePdf(%s) error = %v, want a transient error", path, err)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, common.GCS_FOLDER))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("artifact directory has %d files, want only the valid PDF", len(entries))
+	}
+}
+
+// countingStore counts the uploads to its LocalArtifactStore.
+type countingStore struct {
+	*LocalArtifactStore
+	uploads int
+}
+
+func (s *countingStore) Upload(ctx context.Context, objectPath string, r io.Reader, attrs common.ArtifactAttrs) (common.StoredArtifact, error) {
+	s.uploads++
+	return s.LocalArtifactStore.Upload(ctx, objectPath, r, attrs)
+}
+
+func TestStreamArtifactUnchanged(t *testing.T) {
+	pdf := "%PDF-1.7\n" + strings.Repeat("0", 4096)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, pdf)
+	}))
+	defer server.Close()
+
+	local, err := NewLocalArtifactStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &countingStore{LocalArtifactStore: local}
+	previousStore, previousClient := common.Artifacts, common.HttpClient
+	common.Artifacts, common.HttpClient = store, server.Client()
+	t.Cleanup(func() {
+		common.Artifacts, common.HttpClient = previousStore, previousClient
+	})
+
+	source := ArtifactSource{Url: server.URL + "/judgement.pdf", CrawledAt: time.Now()}
+	first, err := HandlePdf(context.Background(), "judgement", source, nil)
+	if err != nil {
+		t.Fatalf("HandlePdf() error = %v", err)
+	}
+	if first.ETag != `"v1"` {
+		t.Errorf("ETag = %s, want the one of the response", first.ETag)
+	}
+	if string(first.Content) != pdf {
+		t.Errorf("Content has %d bytes, want the downloaded PDF", len(first.Content))
+	}
+
+	withoutValidators := first
+	withoutValidators.ETag = ""
+	changed := first
+	changed.ETag, changed.Sha256 = "", "previous digest"
+
+	tests := []struct {
+		name        string
+		previous    *Artifact
+		unchanged   bool
+		wantContent bool
+	}{
+		{name: "not modified", previous: &first, unchanged: true},
+		{name: "same digest", previous: &withoutValidators, unchanged: true, wantContent: true},
+		{name: "different digest", previous: &changed, unchanged: false, wantContent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads := store.uploads
+			artifact, err := HandlePdf(context.Background(), "judgement", source, tt.previous)
+			if err != nil {
+				t.Fatalf("HandlePdf() error = %v", err)
+			}
+			if artifact.Unchanged != tt.unchanged {
+				t.Errorf("Unchanged = %v, want %v", artifact.Unchanged, tt.unchanged)
+			}
+			if uploaded := store.uploads > uploads; uploaded == tt.unchanged {
+				t.Errorf("uploaded = %v, want %v", uploaded, !tt.unchanged)
+			}
+			if artifact.Link != first.Link || artifact.Sha256 != first.Sha256 {
+				t.Errorf("artifact = %+v, want the link and digest of %+v", artifact, first)
+			}
+			if hasContent := artifact.Content != nil; hasContent != tt.wantContent || (hasContent && string(artifact.Content) != pdf) {
+				t.Errorf("Content has %d bytes, want the downloaded PDF = %v", len(artifact.Content), tt.wantContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+var errStreamClosed = errors.New("artifact stream closed before its end")
+
+// artifactReader streams a download to the artifact store, computing its checksums and validating it on the way.
+// The validation runs on a copy of the stream and its error is returned instead of io.EOF,
+// so the store aborts the upload of an invalid artifact.
+type artifactReader struct {
+	body   io.Reader
+	sha256 hash.Hash
+	md5    hash.Hash
+	size   int64
+
+	validation *io.PipeWriter
+	validated  chan error
+	err        error
+}
+
+func newArtifactReader(body io.Reader, contentType string, validate func(r io.Reader, contentType string) error) *artifactReader {
+	pr, pw := io.Pipe()
+	a := &artifactReader{
+		body:       body,
+		sha256:     sha256.New(),
+		md5:        md5.New(),
+		validation: pw,
+		validated:  make(chan error, 1),
+	}
+
+	go func() {
+		err := validate(pr, contentType)
+		if err == nil {
+			// Validators may decide before the end, the rest of the stream must still be consumed
+			_, err = io.Copy(io.Discard, pr)
+		}
+		// Fails the pending and next writes of Read once the artifact is known to be invalid
+		pr.CloseWithError(err)
+		a.validated <- err
+	}()
+
+	return a
+}
+
+func (a *artifactReader) Read(p []byte) (int, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	n, err := a.body.Read(p)
+	if n > 0 {
+		a.sha256.Write(p[:n])
+		a.md5.Write(p[:n])
+		a.size += int64(n)
+		if _, werr := a.validation.Write(p[:n]); werr != nil {
+			a.err = werr
+			return 0, a.err
+		}
+	}
+
+	switch {
+	case err == io.EOF:
+		a.validation.Close()
+		if verr := <-a.validated; verr != nil {
+			a.err = verr
+			return n, a.err
+		}
+		a.err = io.EOF
+	case err != nil:
+		a.validation.CloseWithError(err)
+		<-a.validated
+		a.err = err
+	}
+	return n, err
+}
+
+// Err returns the error that ended the stream early, the validation or download error, nil when it was read to its end.
+func (a *artifactReader) Err() error {
+	if a.err == io.EOF {
+		return nil
+	}
+	return a.err
+}
+
+// Close stops the validation of a stream that was not read to its end.
+func (a *artifactReader) Close() error {
+	if a.err == nil {
+		a.validation.CloseWithError(errStreamClosed)
+		<-a.validated
+		a.err = errStreamClosed
+	}
+	return nil
+}
+
+func (a *artifactReader) Size() int64 { return a.size }
+
+func (a *artifactReader) Sha256() string { return hex.EncodeToString(a.sha256.Sum(nil)) }
+
+func (a *artifactReader) Md5() []byte { return a.md5.Sum(nil) }
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"mime"
+)
+
+const (
+	// Readers look for the PDF header in the first kilobyte, some files start with garbage
+	pdfHeaderWindow = 1024
+	minPdfSize      = 1024
+	minHtmlSize     = 1024
+)
+
+var pdfMagic = []byte("%PDF-")
+
+// ValidatePdf checks that r, served with contentType, is a PDF document and not an error page.
+// The %PDF header decides, as the site does not always serve its PDFs as application/pdf.
+// An empty contentType is not checked.
+func ValidatePdf(r io.Reader, contentType string) error {
+	head := make([]byte, pdfHeaderWindow)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	rest, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(head[:n], pdfMagic) {
+		if mediaType := mediaTypeOf(contentType); mediaType != "" && mediaType != "application/pdf" {
+			return invalidArtifact("expected a PDF, got %s", mediaType)
+		}
+		return invalidArtifact("missing %s header", pdfMagic)
+	}
+	if size := int64(n) + rest; size < minPdfSize {
+		return invalidArtifact("PDF of %d bytes is too small", size)
+	}
+	return nil
+}
+
+// ValidateHtml checks that r, served with contentType, is a complete judgement page.
+// An empty contentType is not checked.
+func ValidateHtml(r io.Reader, contentType string) error {
+	if mediaType := mediaTypeOf(contentType); mediaType != "" && mediaType != "text/html" {
+		return invalidArtifact("expected HTML, got %s", mediaType)
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(content) < minHtmlSize {
+		return invalidArtifact("HTML of %d bytes is too small", len(content))
+	}
+	if !bytes.Contains(bytes.ToLower(content), []byte("<html")) {
+		return invalidArtifact("missing <html> element")
+	}
+	if !bytes.Contains(content, []byte("divJudgement")) {
+		return invalidArtifact("missing #divJudgement element")
+	}
+	return nil
+}
+
+// mediaTypeOf returns the lower case media type of contentType without its parameters.
+func mediaTypeOf(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// invalidArtifact returns a permanent error wrapping crawler_model.ErrInvalidArtifact.
+func invalidArtifact(format string, args ...any) error {
+	return retry.Permanent(fmt.Errorf("%w: %s", crawler_model.ErrInvalidArtifact, fmt.Sprintf(format, args...)))
+}
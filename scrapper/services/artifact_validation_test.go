@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"strings"
+	"testing"
+)
+
+func TestValidatePdf(t *testing.T) {
+	pdf := "%PDF-1.7\n" + strings.Repeat("0", 2048)
+	errorPage := "<!DOCTYPE html><html><body>Not Found</body></html>" + strings.Repeat(" ", 2048)
+
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		valid       bool
+	}{
+		{name: "pdf", content: pdf, contentType: "application/pdf", valid: true},
+		{name: "pdf served as octet stream", content: pdf, contentType: "application/octet-stream", valid: true},
+		{name: "header after garbage", content: "\r\n" + pdf, contentType: "", valid: true},
+		{name: "error page", content: errorPage, contentType: "text/html; charset=utf-8", valid: false},
+		{name: "error page served as pdf", content: errorPage, contentType: "application/pdf", valid: false},
+		{name: "truncated", content: "%PDF-1.7\n", contentType: "application/pdf", valid: false},
+		{name: "empty", content: "", contentType: "", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, ValidatePdf(strings.NewReader(tt.content), tt.contentType), tt.valid)
+		})
+	}
+}
+
+func TestValidateHtml(t *testing.T) {
+	page := `<!DOCTYPE html><HTML><body><div id="divJudgement">` + strings.Repeat("judgement ", 200) + `</div></body></HTML>`
+	errorPage := "<!DOCTYPE html><html><body>" + strings.Repeat("Service Unavailable ", 100) + "</body></html>"
+
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		valid       bool
+	}{
+		{name: "judgement", content: page, contentType: "text/html; charset=utf-8", valid: true},
+		{name: "without content type", content: page, contentType: "", valid: true},
+		{name: "error page", content: errorPage, contentType: "text/html", valid: false},
+		{name: "served as json", content: page, contentType: "application/json", valid: false},
+		{name: "truncated", content: page[:200], contentType: "text/html", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, ValidateHtml(strings.NewReader(tt.content), tt.contentType), tt.valid)
+		})
+	}
+}
+
+func assertValidation(t *testing.T, err error, valid bool) {
+	t.Helper()
+	if valid {
+		if err != nil {
+			t.Errorf("error = %v, want valid", err)
+		}
+		return
+	}
+	if !errors.Is(err, crawler_model.ErrInvalidArtifact) {
+		t.Fatalf("error = %v, want ErrInvalidArtifact", err)
+	}
+	if class := retry.Classify(err); class != retry.ERROR_CLASS_PERMANENT {
+		t.Errorf("class = %s, want %s", class, retry.ERROR_CLASS_PERMANENT)
+	}
+}
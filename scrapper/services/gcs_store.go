@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/retry"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+// GcsArtifactStore uploads artifacts to a Google Cloud Storage bucket.
+type GcsArtifactStore struct {
+	client *storage.Client
+	bucket string
+}
+
+func NewGcsArtifactStore(client *storage.Client, bucket string) *GcsArtifactStore {
+	return &GcsArtifactStore{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *GcsArtifactStore) Upload(ctx context.Context, objectPath string, r io.Reader, attrs common.ArtifactAttrs) (common.StoredArtifact, error) {
+	// Canceling the context of the writer discards the object, closing it would keep what was written so far
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wc := s.client.Bucket(s.bucket).Object(objectPath).NewWriter(ctx)
+	wc.ContentType = attrs.ContentType
+	wc.Metadata = attrs.Metadata
+	if _, err := io.Copy(wc, r); err != nil {
+		cancel()
+		wc.Close()
+		return common.StoredArtifact{}, classifyGcsError(err)
+	}
+
+	// The object is only written once the writer is closed
+	if err := wc.Close(); err != nil {
+		return common.StoredArtifact{}, classifyGcsError(err)
+	}
+
+	written := wc.Attrs()
+	return common.StoredArtifact{
+		Link: fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.bucket, objectPath),
+		Size: written.Size,
+		Md5:  written.MD5,
+	}, nil
+}
+
+func (s *GcsArtifactStore) Open(ctx context.Context, link string) (io.ReadCloser, error) {
+	objectPath, ok := strings.CutPrefix(link, fmt.Sprintf("https://storage.googleapis.com/%s/", s.bucket))
+	if !ok {
+		return nil, fmt.Errorf("artifact link %q is not in bucket %s", link, s.bucket)
+	}
+
+	r, err := s.client.Bucket(s.bucket).Object(objectPath).NewReader(ctx)
+	if err != nil {
+		return nil, classifyGcsError(err)
+	}
+	return r, nil
+}
+
+// classifyGcsError marks the errors answered by the GCS API with the class of their status code.
+func classifyGcsError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return retry.WithStatus(apiErr.Code, err)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"os"
+	"path/filepath"
+
+	stdUrl "net/url"
+)
+
+// LocalArtifactStore writes artifacts below a local directory, for development and tests.
+type LocalArtifactStore struct {
+	dir string
+}
+
+func NewLocalArtifactStore(dir string) (*LocalArtifactStore, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory %s: %w", absDir, err)
+	}
+	return &LocalArtifactStore{dir: absDir}, nil
+}
+
+// Upload writes the artifact to its path below the directory. The content type and metadata are not kept.
+func (s *LocalArtifactStore) Upload(ctx context.Context, objectPath string, r io.Reader, attrs common.ArtifactAttrs) (common.StoredArtifact, error) {
+	if !filepath.IsLocal(objectPath) {
+		return common.StoredArtifact{}, fmt.Errorf("object path %q escapes the artifact directory", objectPath)
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(objectPath))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return common.StoredArtifact{}, err
+	}
+
+	// Write next to the target and rename, so readers never see a partial artifact
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return common.StoredArtifact{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	digest := md5.New()
+	size, err := io.Copy(io.MultiWriter(tmp, digest), r)
+	if err != nil {
+		tmp.Close()
+		return common.StoredArtifact{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return common.StoredArtifact{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return common.StoredArtifact{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return common.StoredArtifact{}, err
+	}
+
+	link := stdUrl.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return common.StoredArtifact{
+		Link: link.String(),
+		Size: size,
+		Md5:  digest.Sum(nil),
+	}, nil
+}
+
+func (s *LocalArtifactStore) Open(ctx context.Context, link string) (io.ReadCloser, error) {
+	u, err := stdUrl.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("artifact link %q is not a file link", link)
+	}
+
+	path := filepath.FromSlash(u.Path)
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("artifact link %q is outside the artifact directory", link)
+	}
+	return os.Open(path)
+}
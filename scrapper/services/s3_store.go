@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	"lexicon/singapore-supreme-court-crawler/retry"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3ArtifactStore uploads artifacts to an S3 compatible bucket, such as AWS S3 or MinIO.
+type S3ArtifactStore struct {
+	client *minio.Client
+	bucket string
+}
+
+type S3Options struct {
+	Endpoint        string
+	AccessKeyId     string
+	SecretAccessKey string
+	Region          string
+	Bucket          string
+	UseSsl          bool
+}
+
+// NewS3ArtifactStore connects to the endpoint and creates the bucket when it does not exist.
+func NewS3ArtifactStore(ctx context.Context, opts S3Options) (*S3ArtifactStore, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKeyId, opts.SecretAccessKey, ""),
+		Secure: opts.UseSsl,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", opts.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", opts.Bucket, err)
+		}
+	}
+
+	return &S3ArtifactStore{
+		client: client,
+		bucket: opts.Bucket,
+	}, nil
+}
+
+func (s *S3ArtifactStore) Upload(ctx context.Context, objectPath string, r io.Reader, attrs common.ArtifactAttrs) (common.StoredArtifact, error) {
+	// An upload whose reader fails is aborted by the client, multipart ones included
+	info, err := s.client.PutObject(ctx, s.bucket, objectPath, r, -1, minio.PutObjectOptions{
+		ContentType:  attrs.ContentType,
+		UserMetadata: attrs.Metadata,
+	})
+	if err != nil {
+		return common.StoredArtifact{}, classifyS3Error(err)
+	}
+
+	return common.StoredArtifact{
+		Link: fmt.Sprintf("%s/%s/%s", s.client.EndpointURL(), s.bucket, objectPath),
+		Size: info.Size,
+		Md5:  etagMd5(info.ETag),
+	}, nil
+}
+
+// etagMd5 returns the MD5 digest held by the ETag of an object uploaded in a single part.
+// The ETag of a multipart upload, suffixed with the number of parts, is not a digest of the content.
+func etagMd5(etag string) []byte {
+	digest, err := hex.DecodeString(strings.Trim(etag, `"`))
+	if err != nil || len(digest) != 16 {
+		return nil
+	}
+	return digest
+}
+
+func (s *S3ArtifactStore) Open(ctx context.Context, link string) (io.ReadCloser, error) {
+	objectPath, ok := strings.CutPrefix(link, fmt.Sprintf("%s/%s/", s.client.EndpointURL(), s.bucket))
+	if !ok {
+		return nil, fmt.Errorf("artifact link %q is not in bucket %s", link, s.bucket)
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, objectPath, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, classifyS3Error(err)
+	}
+	// GetObject sends no request until the object is read, stat it to report a missing object here
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, classifyS3Error(err)
+	}
+	return object, nil
+}
+
+// classifyS3Error marks the errors answered by the server with the class of their status code.
+// Others are network errors, classified by retry.Classify.
+func classifyS3Error(err error) error {
+	if statusCode := minio.ToErrorResponse(err).StatusCode; statusCode != 0 {
+		return retry.WithStatus(statusCode, err)
+	}
+	return err
+}
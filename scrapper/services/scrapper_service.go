@@ -2,48 +2,317 @@ package services
 
 import (
 	"context"
+	"errors"
 	"lexicon/singapore-supreme-court-crawler/common"
 	"lexicon/singapore-supreme-court-crawler/repository"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 )
 
-func UpsertExtraction(ctx context.Context, extractions []repository.Extraction) error {
+// UpsertExtraction stores extractions and returns the ids of the judgements that were amended.
+// An extraction whose page hash differs from the stored one gets a new version, the previous one is kept in the extraction history.
+func UpsertExtraction(ctx context.Context, extractions []repository.Extraction) ([]string, error) {
 	tx, err := common.Pool.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer tx.Rollback(ctx)
 
 	queries := common.Query.WithTx(tx)
 
-	br := queries.UpsertExtraction(ctx, lo.Map(extractions, func(extraction repository.Extraction, _ int) repository.UpsertExtractionParams {
+	stored, err := queries.GetExtractionHashes(ctx, lo.Map(extractions, func(extraction repository.Extraction, _ int) string {
+		return extraction.ID
+	}))
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting extraction hashes")
+		return nil, err
+	}
+	storedById := lo.KeyBy(stored, func(row repository.GetExtractionHashesRow) string {
+		return row.ID
+	})
+
+	now := time.Now()
+	amendedIds := []string{}
+	archives := []repository.ArchiveExtractionParams{}
+	params := lo.Map(extractions, func(extraction repository.Extraction, _ int) repository.UpsertExtractionParams {
+		version, amendedAt := int32(1), (*time.Time)(nil)
+		if previous, ok := storedById[extraction.ID]; ok {
+			version, amendedAt = previous.Version, previous.AmendedAt
+			if isAmended(previous.PageHash, extraction.PageHash) {
+				version, amendedAt = previous.Version+1, &now
+				amendedIds = append(amendedIds, extraction.ID)
+				archives = append(archives, repository.ArchiveExtractionParams{
+					SupersededAt: now,
+					ID:           extraction.ID,
+				})
+			}
+		}
+
 		return repository.UpsertExtractionParams{
-			ID:            extraction.ID,
-			UrlFrontierID: extraction.UrlFrontierID,
-			SiteContent:   extraction.SiteContent,
-			ArtifactLink:  extraction.ArtifactLink,
-			RawPageLink:   extraction.RawPageLink,
-			Language:      extraction.Language,
-			PageHash:      extraction.PageHash,
-			Metadata:      extraction.Metadata,
-			CreatedAt:     extraction.CreatedAt,
-			UpdatedAt:     extraction.UpdatedAt,
+			ID:                   extraction.ID,
+			UrlFrontierID:        extraction.UrlFrontierID,
+			SiteContent:          extraction.SiteContent,
+			ArtifactLink:         extraction.ArtifactLink,
+			RawPageLink:          extraction.RawPageLink,
+			Language:             extraction.Language,
+			PageHash:             extraction.PageHash,
+			Metadata:             extraction.Metadata,
+			CreatedAt:            extraction.CreatedAt,
+			UpdatedAt:            extraction.UpdatedAt,
+			Version:              version,
+			AmendedAt:            amendedAt,
+			ArtifactSha256:       extraction.ArtifactSha256,
+			ArtifactSize:         extraction.ArtifactSize,
+			RawPageSha256:        extraction.RawPageSha256,
+			RawPageSize:          extraction.RawPageSize,
+			ArtifactEtag:         extraction.ArtifactEtag,
+			ArtifactLastModified: extraction.ArtifactLastModified,
+			RawPageEtag:          extraction.RawPageEtag,
+			RawPageLastModified:  extraction.RawPageLastModified,
+			PdfText:              extraction.PdfText,
+			PdfPageCount:         extraction.PdfPageCount,
+			PdfParagraphPages:    extraction.PdfParagraphPages,
 		}
-	}))
+	})
 
-	br.Exec(func(int, error) {
+	var storeErrors []error
+	if len(archives) > 0 {
+		queries.ArchiveExtraction(ctx, archives).Exec(func(i int, err error) {
+			if err != nil {
+				log.Error().Err(err).Msgf("Error archiving extraction %s", archives[i].ID)
+				storeErrors = append(storeErrors, err)
+			}
+		})
+	}
+
+	queries.UpsertExtraction(ctx, params).Exec(func(i int, err error) {
 		if err != nil {
-			log.Error().Err(err).Msg("Error upserting extractions")
-			return
+			log.Error().Err(err).Msgf("Error upserting extraction %s", params[i].ID)
+			storeErrors = append(storeErrors, err)
 		}
 	})
+	storeErrors = append(storeErrors, upsertJudges(ctx, queries, extractions, now)...)
+	if len(storeErrors) > 0 {
+		return nil, errors.Join(storeErrors...)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return amendedIds, nil
+}
+
+// isAmended reports whether a judgement page changed since it was stored.
+// A stored extraction without a page hash cannot be compared and is never considered amended.
+func isAmended(storedHash *string, pageHash *string) bool {
+	if storedHash == nil || pageHash == nil {
+		return false
+	}
+	return *storedHash != *pageHash
+}
+
+// UpdateExtractionMetadata replaces the metadata of extractions, keeping their content and version.
+func UpdateExtractionMetadata(ctx context.Context, extractions []repository.Extraction) error {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	now := time.Now()
+	params := lo.Map(extractions, func(extraction repository.Extraction, _ int) repository.UpdateExtractionMetadataParams {
+		return repository.UpdateExtractionMetadataParams{
+			Metadata:  extraction.Metadata,
+			UpdatedAt: now,
+			ID:        extraction.ID,
+		}
+	})
+
+	var updateErrors []error
+	queries.UpdateExtractionMetadata(ctx, params).Exec(func(i int, err error) {
+		if err != nil {
+			log.Error().Err(err).Msgf("Error updating metadata of extraction %s", params[i].ID)
+			updateErrors = append(updateErrors, err)
+		}
+	})
+	updateErrors = append(updateErrors, upsertJudges(ctx, queries, extractions, now)...)
+	if len(updateErrors) > 0 {
+		return errors.Join(updateErrors...)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// upsertJudges registers the coram of extractions in the judges registry.
+// The name and role of a judge are those of their latest judgement.
+func upsertJudges(ctx context.Context, queries *repository.Queries, extractions []repository.Extraction, now time.Time) []error {
+	params := judgeParams(extractions, now)
+	if len(params) == 0 {
+		return nil
+	}
 
-	return nil
+	var upsertErrors []error
+	queries.UpsertJudges(ctx, params).Exec(func(i int, err error) {
+		if err != nil {
+			log.Error().Err(err).Msgf("Error upserting judge %s", params[i].ID)
+			upsertErrors = append(upsertErrors, err)
+		}
+	})
+	return upsertErrors
+}
+
+// judgeParams returns one row of the judges registry per judge sitting in extractions, ordered by id.
+// Concurrent scrappers sharing judges then lock their rows in the same order and never deadlock.
+func judgeParams(extractions []repository.Extraction, now time.Time) []repository.UpsertJudgesParams {
+	byId := map[string]*repository.UpsertJudgesParams{}
+	for _, extraction := range extractions {
+		var decisionDate *time.Time
+		if date, err := time.Parse(time.RFC3339, extraction.Metadata.DecisionDate); err == nil {
+			decisionDate = &date
+		}
+		for _, judge := range extraction.Metadata.Coram {
+			if judge.ID == "" {
+				continue
+			}
+
+			param, ok := byId[judge.ID]
+			if !ok {
+				param = &repository.UpsertJudgesParams{ID: judge.ID, FirstDecisionDate: decisionDate, UpdatedAt: now}
+				byId[judge.ID] = param
+			}
+			// Like the registry, an undated judgement only names a judge no dated judgement names
+			if param.LastDecisionDate == nil || (decisionDate != nil && !decisionDate.Before(*param.LastDecisionDate)) {
+				param.Name, param.Honorific, param.Role = judge.Name, judge.Honorific, judge.Role
+				if decisionDate != nil {
+					param.LastDecisionDate = decisionDate
+				}
+			}
+			if decisionDate != nil && (param.FirstDecisionDate == nil || decisionDate.Before(*param.FirstDecisionDate)) {
+				param.FirstDecisionDate = decisionDate
+			}
+		}
+	}
+
+	params := lo.MapToSlice(byId, func(_ string, param *repository.UpsertJudgesParams) repository.UpsertJudgesParams {
+		return *param
+	})
+	slices.SortFunc(params, func(a, b repository.UpsertJudgesParams) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return params
+}
+
+// UpdateExtractionPdfText replaces the text extracted from the PDF of extractions, keeping their content and version.
+func UpdateExtractionPdfText(ctx context.Context, extractions []repository.Extraction) error {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	now := time.Now()
+	params := lo.Map(extractions, func(extraction repository.Extraction, _ int) repository.UpdateExtractionPdfTextParams {
+		return repository.UpdateExtractionPdfTextParams{
+			PdfText:           extraction.PdfText,
+			PdfPageCount:      extraction.PdfPageCount,
+			PdfParagraphPages: extraction.PdfParagraphPages,
+			UpdatedAt:         now,
+			ID:                extraction.ID,
+		}
+	})
+
+	var updateErrors []error
+	queries.UpdateExtractionPdfText(ctx, params).Exec(func(i int, err error) {
+		if err != nil {
+			log.Error().Err(err).Msgf("Error updating pdf text of extraction %s", params[i].ID)
+			updateErrors = append(updateErrors, err)
+		}
+	})
+	if len(updateErrors) > 0 {
+		return errors.Join(updateErrors...)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func ListExtractions(ctx context.Context, since time.Time, afterId string, limit int32) ([]repository.Extraction, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	extractions, err := queries.ListExtractions(ctx, repository.ListExtractionsParams{
+		Crawler:   common.CRAWLER_NAME,
+		UpdatedAt: since,
+		ID:        afterId,
+		Limit:     limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing extractions")
+		return nil, err
+	}
+
+	return extractions, nil
+}
+
+// StoredArtifacts are the artifacts stored for an extraction by a previous scrape.
+type StoredArtifacts struct {
+	// Pdf and Html are nil when they were never stored or stored without their checksum
+	Pdf  *Artifact
+	Html *Artifact
+	// HasPdfText is set once the text of the PDF is stored in the extraction
+	HasPdfText bool
+}
+
+// GetStoredArtifacts returns the PDF and raw page stored for the extraction id by a previous scrape.
+func GetStoredArtifacts(ctx context.Context, id string) (StoredArtifacts, error) {
+	tx, err := common.Pool.Begin(ctx)
+	if err != nil {
+		return StoredArtifacts{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := common.Query.WithTx(tx)
+
+	row, err := queries.GetExtractionArtifacts(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return StoredArtifacts{}, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting extraction artifacts")
+		return StoredArtifacts{}, err
+	}
+
+	return StoredArtifacts{
+		Pdf:        storedArtifact(row.ArtifactLink, row.ArtifactSha256, row.ArtifactSize, row.ArtifactEtag, row.ArtifactLastModified),
+		Html:       storedArtifact(row.RawPageLink, row.RawPageSha256, row.RawPageSize, row.RawPageEtag, row.RawPageLastModified),
+		HasPdfText: row.HasPdfText,
+	}, nil
+}
+
+func storedArtifact(link *string, sha256 *string, size *int64, etag *string, lastModified *string) *Artifact {
+	if link == nil || sha256 == nil || size == nil {
+		return nil
+	}
+	return &Artifact{
+		Link:         *link,
+		Size:         *size,
+		Sha256:       *sha256,
+		ETag:         lo.FromPtr(etag),
+		LastModified: lo.FromPtr(lastModified),
+	}
 }
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/scrapper/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJudgeParams(t *testing.T) {
+	judgement := func(decisionDate string, coram ...models.Judge) repository.Extraction {
+		return repository.Extraction{Metadata: models.Metadata{DecisionDate: decisionDate, Coram: coram}}
+	}
+	date := func(value string) *time.Time {
+		date, _ := time.Parse(time.RFC3339, value)
+		return &date
+	}
+	now := time.Now()
+
+	got := judgeParams([]repository.Extraction{
+		judgement("2023-05-02T00:00:00Z",
+			models.Judge{ID: "sundaresh-menon", Name: "Sundaresh Menon", Honorific: "CJ", Role: "Chief Justice"},
+			models.Judge{ID: "steven-chong", Name: "Steven Chong", Honorific: "JCA", Role: "Justice of the Court of Appeal"},
+		),
+		judgement("2015-01-20T00:00:00Z",
+			models.Judge{ID: "steven-chong", Name: "Steven Chong", Honorific: "J", Role: "Judge"},
+			models.Judge{Name: "unknown"},
+		),
+		judgement("", models.Judge{ID: "steven-chong", Name: "Steven Chong", Honorific: "JC", Role: "Judicial Commissioner"}),
+		judgement("2019-08-01T00:00:00Z", models.Judge{ID: "andrew-phang", Name: "Andrew Phang", Honorific: "JA", Role: "Judge of Appeal"}),
+	}, now)
+
+	want := []repository.UpsertJudgesParams{
+		{ID: "andrew-phang", Name: "Andrew Phang", Honorific: "JA", Role: "Judge of Appeal", FirstDecisionDate: date("2019-08-01T00:00:00Z"), LastDecisionDate: date("2019-08-01T00:00:00Z"), UpdatedAt: now},
+		{ID: "steven-chong", Name: "Steven Chong", Honorific: "JCA", Role: "Justice of the Court of Appeal", FirstDecisionDate: date("2015-01-20T00:00:00Z"), LastDecisionDate: date("2023-05-02T00:00:00Z"), UpdatedAt: now},
+		{ID: "sundaresh-menon", Name: "Sundaresh Menon", Honorific: "CJ", Role: "Chief Justice", FirstDecisionDate: date("2023-05-02T00:00:00Z"), LastDecisionDate: date("2023-05-02T00:00:00Z"), UpdatedAt: now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("judgeParams() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lexicon/singapore-supreme-court-crawler/common"
+	crawler_model "lexicon/singapore-supreme-court-crawler/crawler/models"
+	crawler_service "lexicon/singapore-supreme-court-crawler/crawler/services"
+	"lexicon/singapore-supreme-court-crawler/repository"
+	"lexicon/singapore-supreme-court-crawler/scrapper/services"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// SweepOptions selects the extractions whose artifacts are audited by SweepArtifacts.
+type SweepOptions struct {
+	// Since only sweeps extractions updated at or after it
+	Since     time.Time
+	BatchSize int32
+	// Fix gives the url frontiers of invalid artifacts the invalid_artifact status, so they are scraped again
+	Fix bool
+}
+
+// SweepReport counts the audited artifacts and lists the invalid ones.
+type SweepReport struct {
+	Checked int               `json:"checked"`
+	Invalid int               `json:"invalid"`
+	Fixed   int               `json:"fixed"`
+	Failed  int               `json:"failed"`
+	Issues  []InvalidArtifact `json:"issues"`
+}
+
+// InvalidArtifact is an uploaded artifact rejected by the artifact validation.
+type InvalidArtifact struct {
+	ExtractionID  string `json:"extraction_id"`
+	UrlFrontierID string `json:"url_frontier_id"`
+	Kind          string `json:"kind"`
+	Link          string `json:"link"`
+	Reason        string `json:"reason"`
+}
+
+type artifactCheck struct {
+	kind     string
+	link     *string
+	validate func(r io.Reader, contentType string) error
+}
+
+// SweepArtifacts reads back the PDF and raw page of the stored extractions from the artifact store and
+// validates them like fresh downloads, to find the error pages uploaded before downloads were validated.
+// Artifacts that cannot be read are counted as failed, not invalid.
+func SweepArtifacts(ctx context.Context, opts SweepOptions) (SweepReport, error) {
+	if common.Artifacts == nil {
+		return SweepReport{}, errors.New("artifact store is not set")
+	}
+
+	report := SweepReport{Issues: []InvalidArtifact{}}
+	afterId := ""
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		extractions, err := services.ListExtractions(ctx, opts.Since, afterId, opts.BatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(extractions) == 0 {
+			return report, nil
+		}
+		afterId = extractions[len(extractions)-1].ID
+
+		issues := []InvalidArtifact{}
+		for _, extraction := range extractions {
+			checks := []artifactCheck{
+				{kind: "pdf", link: extraction.ArtifactLink, validate: services.ValidatePdf},
+				{kind: "html", link: extraction.RawPageLink, validate: services.ValidateHtml},
+			}
+			for _, check := range checks {
+				if check.link == nil || *check.link == "" {
+					continue
+				}
+
+				err := validateArtifact(ctx, *check.link, check.validate)
+				if err != nil && !errors.Is(err, crawler_model.ErrInvalidArtifact) {
+					log.Error().Err(err).Msgf("Error reading artifact %s", *check.link)
+					report.Failed++
+					continue
+				}
+				report.Checked++
+				if err == nil {
+					continue
+				}
+
+				log.Warn().Err(err).Msgf("Invalid %s artifact for extraction %s", check.kind, extraction.ID)
+				issues = append(issues, InvalidArtifact{
+					ExtractionID:  extraction.ID,
+					UrlFrontierID: extraction.UrlFrontierID,
+					Kind:          check.kind,
+					Link:          *check.link,
+					Reason:        err.Error(),
+				})
+			}
+		}
+		report.Invalid += len(issues)
+		report.Issues = append(report.Issues, issues...)
+
+		if !opts.Fix || len(issues) == 0 {
+			continue
+		}
+		fixed, err := markInvalidArtifacts(ctx, issues)
+		if err != nil {
+			return report, fmt.Errorf("failed to mark invalid artifacts: %w", err)
+		}
+		report.Fixed += fixed
+	}
+}
+
+func validateArtifact(ctx context.Context, link string, validate func(r io.Reader, contentType string) error) error {
+	r, err := common.Artifacts.Open(ctx, link)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// The store does not keep the content type of the download, only the content is validated
+	return validate(r, "")
+}
+
+// markInvalidArtifacts gives the url frontiers of issues the invalid_artifact status, due now,
+// and returns how many url frontiers were marked. Their attempts are left as they are.
+func markInvalidArtifacts(ctx context.Context, issues []InvalidArtifact) (int, error) {
+	reasons := map[string]string{}
+	for _, issue := range issues {
+		reasons[issue.UrlFrontierID] = fmt.Sprintf("%s artifact: %s", issue.Kind, issue.Reason)
+	}
+
+	urlFrontiers, err := crawler_service.GetUrlFrontiersByIds(ctx, lo.Keys(reasons))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	attempts := lo.Map(urlFrontiers, func(urlFrontier repository.UrlFrontier, _ int) repository.UpdateUrlFrontierAttemptParams {
+		reason := reasons[urlFrontier.ID]
+		return repository.UpdateUrlFrontierAttemptParams{
+			ID:            urlFrontier.ID,
+			Status:        crawler_model.URL_FRONTIER_STATUS_INVALID_ARTIFACT,
+			Attempts:      urlFrontier.Attempts,
+			LastError:     &reason,
+			NextAttemptAt: &now,
+			UpdatedAt:     now,
+		}
+	})
+	if len(attempts) == 0 {
+		return 0, nil
+	}
+	if err := crawler_service.UpdateUrlFrontierAttempts(ctx, attempts); err != nil {
+		return 0, err
+	}
+	return len(attempts), nil
+}